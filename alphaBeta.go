@@ -3,28 +3,57 @@ package gotack
 import "math"
 
 func (e *Evaluator) alphaBeta(depth int, alpha, beta float64, isMaximizingPlayer bool, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	ply := e.Depth - depth
+	e.reachDepth(ply)
+	e.pvTable[ply] = e.pvTable[ply][:0]
+	if e.repeated(depth) {
+		return e.drawScore(), nil
+	}
+	defer e.leavePosition(depth)
+
+	if value, ok := e.probeTablebase(e.Board, isMaximizingPlayer, ply); ok {
+		return value, nil
+	}
+
 	if depth == 0 || e.Board.IsGameOver() {
-		opts.Extra["depth"] = e.Depth - depth
-		return e.Board.EvaluateFunc(*opts), nil
+		opts.Extra["depth"] = ply
+		return e.evaluateLeaf(isMaximizingPlayer, ply, opts), nil
+	}
+
+	moves := e.legalMoves(e.Board, isMaximizingPlayer)
+	if len(moves) == 0 {
+		return e.noMovesValue(isMaximizingPlayer, ply), nil
+	}
+	if ply == 0 {
+		moves = e.rootMoves(moves)
 	}
 
 	var bestMoves []Move
 	var eval float64
 	if isMaximizingPlayer {
 		maxEval := math.Inf(-1)
-		for _, move := range e.Board.GetAllMoves(isMaximizingPlayer) {
+		for i, move := range moves {
 			e.Board.Move(move)
 			eval, _ = e.alphaBeta(depth-1, alpha, beta, false, opts)
 			e.Board.UndoMove(move)
+			if e.stopped {
+				break // 中止的子节点的值不完整，不参与比较
+			}
 
 			if eval > maxEval {
 				maxEval = eval
 				bestMoves = []Move{move}
+				e.updatePV(ply, move)
 			} else if eval == maxEval {
 				bestMoves = append(bestMoves, move)
 			}
 			alpha = math.Max(alpha, eval)
 			if beta <= alpha {
+				e.cutoff(i)
 				break
 			}
 		}
@@ -34,19 +63,24 @@ func (e *Evaluator) alphaBeta(depth int, alpha, beta float64, isMaximizingPlayer
 		return maxEval, bestMoves
 	} else {
 		minEval := math.Inf(1)
-		for _, move := range e.Board.GetAllMoves(isMaximizingPlayer) {
+		for i, move := range moves {
 			e.Board.Move(move)
 			eval, _ = e.alphaBeta(depth-1, alpha, beta, true, opts)
 			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
 
 			if eval < minEval {
 				minEval = eval
 				bestMoves = []Move{move}
+				e.updatePV(ply, move)
 			} else if eval == minEval {
 				bestMoves = append(bestMoves, move)
 			}
 			beta = math.Min(beta, eval)
 			if beta <= alpha {
+				e.cutoff(i)
 				break
 			}
 		}
@@ -0,0 +1,187 @@
+package gotack
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxAnalysisDepth = 128                    // 分析模式下依赖深度的算法迭代加深的最大深度
+	infoInterval     = 100 * time.Millisecond // MCTS 分析时两次进度回调之间的最短间隔
+)
+
+// RootVisit 表示 MCTS 根节点一个走法的统计。
+type RootVisit struct {
+	Move   Move
+	Visits int
+	// Value 为该走法以最大化玩家视角的平均奖励。
+	Value float64
+}
+
+// SearchInfo 表示分析过程中的一次搜索进度。
+type SearchInfo struct {
+	// Depth 为已完成的迭代深度，MCTS 类算法为主要变例的长度。
+	Depth int
+	// Value 为根节点以最大化玩家视角的评估值。
+	Value float64
+	// MateIn 为根节点行棋方已知的胜负距离，含义与 SearchResult.MateIn 相同。
+	MateIn int
+	// PV 为当前的主要变例。
+	PV []Move
+	// Nodes 为已访问的节点数，MCTS 类算法为模拟次数。
+	Nodes int64
+	// NPS 为每秒访问的节点数。
+	NPS int64
+	// Elapsed 为分析已经使用的时间。
+	Elapsed time.Duration
+	// RootVisits 为 MCTS 根节点各走法的访问次数分布，按访问次数从多到少排列，其余算法为 nil。
+	RootVisits []RootVisit
+	// Lines 为 MultiPV 大于 1 时根节点最好的若干个走法，含义与 SearchResult.Lines 相同。
+	Lines []PVLine
+}
+
+// Analysis 表示一次在后台进行的无限分析。
+type Analysis struct {
+	evaluator *Evaluator
+	stop      atomic.Bool
+	done      chan struct{}
+	result    *SearchResult
+	err       error
+}
+
+// Analyze 在当前局面的克隆上开始不受深度与时间限制的后台分析，并通过 onInfo 持续报告搜索进度，直到调用 Stop。
+// AlphaBeta/PVS、Expectimax、Star1/Star2 与多人算法每完成一次迭代加深报告一次；
+// UCT 与 ISMCTS 每隔 infoInterval 报告一次根节点的访问分布，SimultaneousUCT 每隔 infoInterval 报告一次当前概率最大的走法；
+// ProofNumber/DFPN 证明结束或停止时报告一次。分析结束时总会以最终结果再报告一次。
+// onInfo 在后台 goroutine 中调用，不应长时间阻塞；并行 UCT 只报告最终结果。e.Board 不会被修改。
+func (e *Evaluator) Analyze(onInfo func(SearchInfo)) *Analysis {
+	a := &Analysis{done: make(chan struct{})}
+	a.evaluator = e.spawn(e.Board.Clone(), &a.stop)
+	a.evaluator.onInfo = onInfo
+	opts := a.evaluator.EvalOptions
+	opts.Iterations = 0
+	if e.TreeType.usesDepth() {
+		opts.Depth = maxAnalysisDepth
+		a.evaluator.Depth = maxAnalysisDepth
+	}
+	go func() {
+		defer close(a.done)
+		a.result, a.err = a.evaluator.Search()
+		if a.err == nil && onInfo != nil {
+			onInfo(a.evaluator.searchInfo(a.evaluator.completedDepth(), a.result.Value, a.result.PV, a.evaluator.tree))
+		}
+	}()
+	return a
+}
+
+// Stop 停止分析，等待后台搜索结束并返回最终结果。
+func (a *Analysis) Stop() (*SearchResult, error) {
+	a.stop.Store(true)
+	return a.Wait()
+}
+
+// Wait 等待分析自然结束（例如搜索到了最大深度）并返回最终结果。
+func (a *Analysis) Wait() (*SearchResult, error) {
+	<-a.done
+	return a.result, a.err
+}
+
+// Done 返回一个在分析结束时关闭的通道。
+func (a *Analysis) Done() <-chan struct{} {
+	return a.done
+}
+
+// spawn 创建在 board 上进行后台搜索的评估器，它拥有独立的选项副本，不受时间限制，直到 stop 被设置。
+func (e *Evaluator) spawn(board Board, stop *atomic.Bool) *Evaluator {
+	background := NewEvaluator(e.TreeType, cloneOptions(e.EvalOptions, board))
+	background.infinite = true
+	background.stopFlag = stop
+	return background
+}
+
+// cloneOptions 返回 src 以 board 为棋盘的副本，Extra 被复制且不输出详细信息，用于在其他局面上进行独立的搜索。
+func cloneOptions(src *EvalOptions, board Board) *EvalOptions {
+	opts := *src
+	opts.Board = board
+	opts.IsDetail = false
+	opts.Extra = make(map[string]interface{}, len(src.Extra))
+	for key, value := range src.Extra {
+		opts.Extra[key] = value
+	}
+	return &opts
+}
+
+// completedDepth 返回最近一次搜索已完成的深度：迭代加深为最后一次完整迭代的深度，其余算法为主要变例的长度。
+func (e *Evaluator) completedDepth() int {
+	if e.lastDepth > 0 {
+		return e.lastDepth
+	}
+	return len(e.pv)
+}
+
+// searchInfo 根据当前的搜索状态生成一次进度报告。
+func (e *Evaluator) searchInfo(depth int, value float64, pv []Move, root *Node) SearchInfo {
+	elapsed := e.tm.elapsed()
+	info := SearchInfo{
+		Depth:   depth,
+		Value:   value,
+		MateIn:  mateIn(value, e.EvalOptions.IsMaxPlayer),
+		PV:      append([]Move(nil), pv...),
+		Nodes:   e.nodes,
+		Elapsed: elapsed,
+		Lines:   e.lines,
+	}
+	if elapsed > 0 {
+		info.NPS = int64(float64(e.nodes) / elapsed.Seconds())
+	}
+	if root != nil {
+		if e.EvalOptions.MultiPV > 1 {
+			info.Lines = e.treeLines(root)
+		}
+		for _, child := range root.Children {
+			if child.Visits > 0 {
+				info.RootVisits = append(info.RootVisits, RootVisit{
+					Move:   child.Move,
+					Visits: child.Visits,
+					Value:  child.TotalReward / float64(child.Visits),
+				})
+			}
+		}
+		sort.SliceStable(info.RootVisits, func(i, j int) bool {
+			return info.RootVisits[i].Visits > info.RootVisits[j].Visits
+		})
+	}
+	return info
+}
+
+// infoDue 检查分析时距离上一次进度报告是否已经过了 infoInterval，是则记录本次报告的时间。
+func (e *Evaluator) infoDue(lastInfo *time.Duration) bool {
+	if e.onInfo == nil {
+		return false
+	}
+	elapsed := e.tm.elapsed()
+	if elapsed-*lastInfo < infoInterval {
+		return false
+	}
+	*lastInfo = elapsed
+	return true
+}
+
+// reportTree 在 MCTS 分析时按 infoInterval 的间隔报告根节点的搜索进度。
+func (e *Evaluator) reportTree(root *Node, lastInfo *time.Duration) {
+	if !e.infoDue(lastInfo) {
+		return
+	}
+	var best *Node
+	for _, child := range root.Children {
+		if best == nil || child.Visits > best.Visits {
+			best = child
+		}
+	}
+	if best == nil || best.Visits == 0 {
+		return
+	}
+	pv := principalVariation(best)
+	e.onInfo(e.searchInfo(len(pv), best.TotalReward/float64(best.Visits), pv, root))
+}
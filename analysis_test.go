@@ -0,0 +1,62 @@
+package gotack
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeReportsUntilStopped(t *testing.T) {
+	var samples []int
+	tests := []struct {
+		tt    GameTreeType
+		board Board
+	}{
+		{AlphaBeta, newPickBoard(1000, 1, 2, 3)},
+		{PVS, newPickBoard(1000, 1, 2, 3)},
+		{UCT, newPickBoard(1000, 1, 2, 3)},
+		{Expectimax, newDiceBoard(1000, -3, 1, 4)},
+		{Star2, newDiceBoard(1000, -3, 1, 4)},
+		{ISMCTS, &cardBoard{samples: &samples}},
+		{SimultaneousUCT, &penniesBoard{}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.tt), func(t *testing.T) {
+			samples = samples[:0]
+			e := NewEvaluator(tc.tt, NewEvaluatorOptions(WithBoard(tc.board), WithDepth(2), WithIterations(100)))
+			var infos atomic.Int32
+			a := e.Analyze(func(SearchInfo) { infos.Add(1) })
+
+			deadline := time.After(5 * time.Second)
+			for infos.Load() < 2 {
+				select {
+				case <-a.Done():
+					t.Fatalf("analysis ended by itself after %d reports", infos.Load())
+				case <-deadline:
+					t.Fatalf("%d reports after 5s, want progress reports before Stop", infos.Load())
+				case <-time.After(10 * time.Millisecond):
+				}
+			}
+
+			stopped := make(chan struct{})
+			var result *SearchResult
+			var err error
+			go func() {
+				result, err = a.Stop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Stop did not end the analysis")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.BestMoves) == 0 {
+				t.Fatalf("no best move after Stop")
+			}
+		})
+	}
+}
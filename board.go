@@ -1,5 +1,7 @@
 package gotack
 
+import "math/rand"
+
 // Board 是棋盘的接口，需要实现该棋盘的全部方法，才可以调用博弈树。
 type Board interface {
 	// Print 打印棋盘的当前状态。
@@ -40,3 +42,140 @@ type Move interface {
 	// String 返回一个表示棋盘上一步棋动作的字符串。
 	String() string
 }
+
+// PriorBoard 是可选接口，棋盘实现该接口后可以为 MCTS 提供走法的先验概率（例如来自策略网络）。
+type PriorBoard interface {
+	// MovePriors 返回 moves 中每个走法的先验概率。
+	// 参数:
+	//   - moves []Move: 当前局面下需要计算先验的走法。
+	//   - isMaxPlayer bool: 当前行棋方是否为最大化玩家。
+	// 返回值:
+	//   - []float64: 与 moves 一一对应的先验概率，和应为 1。
+	MovePriors(moves []Move, isMaxPlayer bool) []float64
+}
+
+// ChanceOutcome 表示机会节点上的一个随机结果（如一次掷骰或发牌）。
+type ChanceOutcome struct {
+	// Move 是该随机结果对应的走法，通过 Board.Move 应用、Board.UndoMove 撤销。
+	Move Move
+	// Probability 是该结果出现的概率，同一机会节点上所有结果的概率之和应为 1。
+	Probability float64
+}
+
+// ChanceBoard 是可选接口，含有随机因素（骰子、发牌等）的棋盘实现该接口后，
+// 可以使用 Expectimax、Star1、Star2 算法，UCT 也会按概率对机会结果进行采样。
+// 机会节点上的随机结果不改变行棋方，随机结果应用后仍由原来的玩家走棋。
+type ChanceBoard interface {
+	// IsChanceNode 检查当前局面是否为机会节点，即下一步由随机事件而非玩家决定。
+	IsChanceNode() bool
+
+	// ChanceOutcomes 返回当前机会节点的全部随机结果及其概率。
+	ChanceOutcomes() []ChanceOutcome
+}
+
+// MultiPlayerBoard 是可选接口，三人及以上的棋盘实现该接口后可以使用 MaxN、Paranoid、BRS 算法，
+// UCT 也会改为按玩家编号反向传播分数向量。
+// 多人模式下 GetAllMoves 应根据 CurrentPlayer 生成走法，其 isMaxPlayer 参数表示当前玩家是否为根节点的行棋方。
+type MultiPlayerBoard interface {
+	// NumPlayers 返回玩家人数，玩家编号为 0 到 NumPlayers()-1。
+	NumPlayers() int
+
+	// CurrentPlayer 返回当前行棋方的玩家编号。
+	CurrentPlayer() int
+
+	// PlayerScores 返回当前局面下每个玩家的评估分数，下标为玩家编号，分数越大对该玩家越有利。
+	PlayerScores(opts EvalOptions) []float64
+}
+
+// BestReplyBoard 是可选接口，BRS（Best-Reply Search）需要让任意对手在其回合之外走棋。
+type BestReplyBoard interface {
+	MultiPlayerBoard
+
+	// SetCurrentPlayer 将当前行棋方设置为指定玩家，之后的 GetAllMoves 与 Move 都以该玩家身份进行。
+	SetCurrentPlayer(player int)
+}
+
+// Determinizer 是可选接口，含隐藏信息的棋盘（如牌类游戏）实现该接口后可以使用 ISMCTS 算法。
+// 搜索器不会直接读取真实的隐藏状态，而是每次迭代都通过 Determinize 采样一个与已知信息一致的确定化棋盘。
+type Determinizer interface {
+	// Determinize 根据指定玩家可见的信息随机采样一个完整的棋盘，隐藏信息（如对手手牌）在其中被具体化。
+	// 参数:
+	//   - isMaxPlayer bool: 观察者是否为最大化玩家，采样结果只能依赖该玩家已知的信息。
+	//   - rng *rand.Rand: 本次搜索的随机数生成器，采样应使用它以保证结果可复现。
+	// 返回值:
+	//   - Board: 一个新的确定化棋盘，搜索会在其上 Move/UndoMove，不会影响原棋盘。
+	Determinize(isMaxPlayer bool, rng *rand.Rand) Board
+}
+
+// SimultaneousBoard 是可选接口，双方同时行动的棋盘实现该接口后可以使用 SimultaneousUCT 算法。
+// 每一步双方分别从 GetAllMoves(true) 与 GetAllMoves(false) 中各选一个走法，
+// 再通过 JointMove 组合成联合走法，由 Board.Move/UndoMove 一次性应用或撤销。
+type SimultaneousBoard interface {
+	// JointMove 将最大化玩家与最小化玩家各自的走法组合成一个联合走法。
+	JointMove(maxMove, minMove Move) Move
+}
+
+// GameResult 表示以当前行棋方视角的对局结果。
+type GameResult int
+
+const (
+	Unknown GameResult = iota // 结果未知（对局未结束或未能证明）
+	Win                       // 当前行棋方获胜
+	Loss                      // 当前行棋方失败
+	Draw                      // 和棋
+)
+
+// String 返回对局结果的名称。
+func (r GameResult) String() string {
+	switch r {
+	case Win:
+		return "Win"
+	case Loss:
+		return "Loss"
+	case Draw:
+		return "Draw"
+	default:
+		return "Unknown"
+	}
+}
+
+// ResultBoard 是可选接口，棋盘实现该接口后可以明确给出终局结果，
+// 否则终局结果由 EvaluateFunc 的符号推断（大于 0 对最大化玩家有利）。
+// UCT 与 ISMCTS 以评估值界限作为胜负的奖励，使用该接口时需要通过 WithEvalBounds 设置界限。
+type ResultBoard interface {
+	// Result 返回终局时以当前行棋方视角的结果，只在 IsGameOver 为 true 时调用。
+	Result() GameResult
+}
+
+// PassBoard 是可选接口，允许停一手的棋盘（如黑白棋、围棋）实现该接口并将 NoMoves 设置为 NoMovesPass 后，
+// 行棋方没有合法走法时搜索会以停着代替，而不是把局面当作叶节点。
+// 停着由 Board.Move/UndoMove 应用和撤销，应用后轮到对方行棋；连续停着等终局条件由 IsGameOver 判断。
+type PassBoard interface {
+	// PassMove 返回指定玩家的停着。
+	PassMove(isMaxPlayer bool) Move
+}
+
+// PositionEnumerator 是可选接口，能够枚举全部局面的棋盘实现该接口后可以通过 GenerateTablebase 进行逆向分析生成残局库。
+// 枚举的局面必须在走法下封闭：任一非终局局面走一步之后的局面也必须被枚举。
+type PositionEnumerator interface {
+	// EnumeratePositions 对每个局面调用一次 visit，isMaxPlayer 表示该局面的行棋方。
+	// 生成器会克隆传入的棋盘，实现者可以复用同一个棋盘对象。要求 Hash 能够区分行棋方。
+	EnumeratePositions(visit func(board Board, isMaxPlayer bool))
+}
+
+// PredecessorBoard 是可选接口，能够生成前驱局面的棋盘实现该接口后，残局库生成时不再需要保存每个局面的后继局面，
+// 从而显著减少内存占用。
+type PredecessorBoard interface {
+	// Predecessors 返回走一步即可到达当前局面的全部局面，当前局面的行棋方为 isMaxPlayer，前驱局面的行棋方为 !isMaxPlayer。
+	// 未被 PositionEnumerator 枚举的前驱局面会被忽略。
+	Predecessors(isMaxPlayer bool) []Board
+}
+
+// PerfectIndexer 是可选接口，棋盘实现该接口后残局库按完美索引存储，每个局面只占用 2 个字节而无需保存哈希。
+type PerfectIndexer interface {
+	// PositionIndex 返回当前局面（含行棋方）在 [0, PositionCount()) 内唯一的下标。
+	PositionIndex() uint64
+
+	// PositionCount 返回索引空间的大小。
+	PositionCount() uint64
+}
@@ -0,0 +1,350 @@
+package gotack
+
+import (
+	"math/rand"
+)
+
+// ExtensiveGame 是两人零和扩展式博弈的接口，与 Board 并列，用于求解扑克等小型不完全信息博弈。
+// 与 Board 一样，博弈状态通过 Move/UndoMove 原地修改，求解器在遍历结束后总会将状态复原。
+type ExtensiveGame interface {
+	// IsTerminal 检查当前历史是否为终局。
+	IsTerminal() bool
+
+	// Utility 返回终局时玩家 player（0 或 1）的收益，两名玩家的收益之和应为 0。
+	Utility(player int) float64
+
+	// IsChance 检查当前历史是否为机会节点（如发牌）。
+	IsChance() bool
+
+	// ChanceOutcomes 返回机会节点的全部随机结果及其概率。
+	ChanceOutcomes() []ChanceOutcome
+
+	// CurrentPlayer 返回当前行棋方的编号（0 或 1）。
+	CurrentPlayer() int
+
+	// InfoSetKey 返回当前行棋方所处信息集的唯一标识，只能依赖该玩家可见的信息。
+	InfoSetKey() string
+
+	// Actions 返回当前行棋方的全部合法动作，同一信息集中的动作及其顺序必须相同。
+	Actions() []Move
+
+	// Move 应用一个动作或随机结果。
+	Move(move Move)
+
+	// UndoMove 撤销一个动作或随机结果。
+	UndoMove(move Move)
+}
+
+// CFRType 表示反事实遗憾最小化的变体。
+type CFRType int
+
+const (
+	VanillaCFR          CFRType = iota // 每次迭代遍历完整的博弈树
+	OutcomeSamplingCFR                 // 每次迭代只采样一条终局路径（蒙特卡洛 CFR）
+	ExternalSamplingCFR                // 采样机会节点与对手的动作，遍历者的动作全部展开（蒙特卡洛 CFR）
+)
+
+// infoSetNode 保存一个信息集上的累计遗憾与累计策略。
+type infoSetNode struct {
+	actions     []Move
+	regretSum   []float64
+	strategySum []float64
+	strategy    []float64 // 本轮遍历使用的当前策略
+	iteration   int       // strategy 对应的遍历编号
+}
+
+// CFRSolver 使用反事实遗憾最小化（CFR）求解两人零和扩展式博弈，得到每个信息集上的平均策略，
+// 平均策略随迭代次数增加收敛到纳什均衡。
+type CFRSolver struct {
+	Game ExtensiveGame
+	Type CFRType
+
+	// Exploration 表示结果采样中遍历者的探索比例 ε，默认为 0.6。
+	Exploration float64
+
+	// Iterations 表示已经完成的训练迭代次数。
+	Iterations int
+
+	// Nodes 表示训练中累计访问的历史数。
+	Nodes int64
+
+	infoSets  map[string]*infoSetNode
+	rng       *rand.Rand
+	traversal int   // 遍历编号，用于在一次遍历内固定当前策略
+	nodeLimit int64 // 累计访问历史数的上限，0 表示不限制
+}
+
+// NewCFRSolver 创建并初始化一个 CFRSolver 对象。
+// 参数:
+//   - game: 要求解的博弈，求解器会在其上原地 Move/UndoMove。
+//   - cfrType: CFR 的变体。
+//   - opts: 评估选项，其中的 Seed 或 RandSource 用于蒙特卡洛变体的采样，MaxNodes 与技能等级限制训练访问的历史数。
+func NewCFRSolver(game ExtensiveGame, cfrType CFRType, opts *EvalOptions) *CFRSolver {
+	return &CFRSolver{
+		Game:        game,
+		Type:        cfrType,
+		Exploration: 0.6,
+		infoSets:    make(map[string]*infoSetNode),
+		rng:         opts.newRand(),
+		nodeLimit:   opts.nodeBudget(),
+	}
+}
+
+// Train 执行指定次数的 CFR 迭代，每次迭代两名玩家轮流作为遍历者更新遗憾。
+// 设置了节点预算时，累计访问的历史数达到预算后不再开始新的迭代；已开始的迭代总会完成，
+// 以免只更新了一部分信息集的遗憾，因此 Nodes 最多超出预算一次迭代的访问量。
+func (s *CFRSolver) Train(iterations int) {
+	for i := 0; i < iterations; i++ {
+		if s.nodeLimit > 0 && s.Nodes >= s.nodeLimit {
+			break
+		}
+		for player := 0; player < 2; player++ {
+			s.traversal++
+			switch s.Type {
+			case OutcomeSamplingCFR:
+				s.outcomeSampling(player, 1, 1, 1)
+			case ExternalSamplingCFR:
+				s.externalSampling(player)
+			default:
+				s.vanilla(player, 1, 1)
+			}
+		}
+		s.Iterations++
+	}
+}
+
+// AverageStrategy 返回指定信息集上的平均策略，信息集从未被访问时返回 nil。
+func (s *CFRSolver) AverageStrategy(infoSetKey string) []MoveProbability {
+	node, ok := s.infoSets[infoSetKey]
+	if !ok {
+		return nil
+	}
+	average := node.averageStrategy()
+	strategy := make([]MoveProbability, len(node.actions))
+	for i, action := range node.actions {
+		strategy[i] = MoveProbability{Move: action, Probability: average[i]}
+	}
+	return strategy
+}
+
+// AverageStrategies 返回全部已访问信息集上的平均策略，键为 InfoSetKey。
+func (s *CFRSolver) AverageStrategies() map[string][]MoveProbability {
+	strategies := make(map[string][]MoveProbability, len(s.infoSets))
+	for key := range s.infoSets {
+		strategies[key] = s.AverageStrategy(key)
+	}
+	return strategies
+}
+
+// vanilla 执行一次完整的 CFR 遍历，返回当前历史对遍历者 player 的期望收益。
+// reachSelf 与 reachOthers 分别是遍历者与其他参与者（对手与机会）到达当前历史的概率。
+func (s *CFRSolver) vanilla(player int, reachSelf, reachOthers float64) float64 {
+	s.Nodes++
+	g := s.Game
+	if g.IsTerminal() {
+		return g.Utility(player)
+	}
+	if g.IsChance() {
+		value := 0.0
+		for _, outcome := range g.ChanceOutcomes() {
+			g.Move(outcome.Move)
+			value += outcome.Probability * s.vanilla(player, reachSelf, reachOthers*outcome.Probability)
+			g.UndoMove(outcome.Move)
+		}
+		return value
+	}
+
+	node := s.infoSet()
+	strategy := s.currentStrategy(node)
+	utils := make([]float64, len(node.actions))
+	nodeUtil := 0.0
+	acting := g.CurrentPlayer() == player
+	for i, action := range node.actions {
+		g.Move(action)
+		if acting {
+			utils[i] = s.vanilla(player, reachSelf*strategy[i], reachOthers)
+		} else {
+			utils[i] = s.vanilla(player, reachSelf, reachOthers*strategy[i])
+		}
+		g.UndoMove(action)
+		nodeUtil += strategy[i] * utils[i]
+	}
+
+	if acting {
+		for i := range node.actions {
+			node.regretSum[i] += reachOthers * (utils[i] - nodeUtil)
+			node.strategySum[i] += reachSelf * strategy[i]
+		}
+	}
+	return nodeUtil
+}
+
+// externalSampling 执行一次外部采样 MCCFR 遍历：机会节点与对手节点各采样一个动作，
+// 遍历者的节点展开全部动作。对手节点上按当前策略累加平均策略。返回遍历者的采样收益。
+func (s *CFRSolver) externalSampling(player int) float64 {
+	s.Nodes++
+	g := s.Game
+	if g.IsTerminal() {
+		return g.Utility(player)
+	}
+	if g.IsChance() {
+		move := sampleOutcome(g.ChanceOutcomes(), s.rng)
+		g.Move(move)
+		value := s.externalSampling(player)
+		g.UndoMove(move)
+		return value
+	}
+
+	node := s.infoSet()
+	strategy := s.currentStrategy(node)
+	if g.CurrentPlayer() != player {
+		for i := range node.actions {
+			node.strategySum[i] += strategy[i]
+		}
+		action := node.actions[sampleIndex(strategy, s.rng)]
+		g.Move(action)
+		value := s.externalSampling(player)
+		g.UndoMove(action)
+		return value
+	}
+
+	utils := make([]float64, len(node.actions))
+	nodeUtil := 0.0
+	for i, action := range node.actions {
+		g.Move(action)
+		utils[i] = s.externalSampling(player)
+		g.UndoMove(action)
+		nodeUtil += strategy[i] * utils[i]
+	}
+	for i := range node.actions {
+		node.regretSum[i] += utils[i] - nodeUtil
+	}
+	return nodeUtil
+}
+
+// outcomeSampling 执行一次结果采样 MCCFR 遍历，只沿一条采样路径到达终局。
+// reachSelf、reachOthers 为遍历者与对手的到达概率，sample 为采样到当前历史的概率。
+// 返回经重要性加权的遍历者收益以及从当前历史之后到终局的遍历策略概率 tail。
+func (s *CFRSolver) outcomeSampling(player int, reachSelf, reachOthers, sample float64) (float64, float64) {
+	s.Nodes++
+	g := s.Game
+	if g.IsTerminal() {
+		return g.Utility(player) / sample, 1
+	}
+	if g.IsChance() {
+		// 机会节点按真实概率采样，采样概率与机会概率相互抵消
+		move := sampleOutcome(g.ChanceOutcomes(), s.rng)
+		g.Move(move)
+		util, tail := s.outcomeSampling(player, reachSelf, reachOthers, sample)
+		g.UndoMove(move)
+		return util, tail
+	}
+
+	node := s.infoSet()
+	strategy := s.currentStrategy(node)
+	acting := g.CurrentPlayer() == player
+	probs := strategy
+	if acting {
+		probs = make([]float64, len(strategy))
+		for i := range strategy {
+			probs[i] = s.Exploration/float64(len(strategy)) + (1-s.Exploration)*strategy[i]
+		}
+	}
+	a := sampleIndex(probs, s.rng)
+	action := node.actions[a]
+
+	var util, tail float64
+	g.Move(action)
+	if acting {
+		util, tail = s.outcomeSampling(player, reachSelf*strategy[a], reachOthers, sample*probs[a])
+	} else {
+		util, tail = s.outcomeSampling(player, reachSelf, reachOthers*strategy[a], sample*probs[a])
+	}
+	g.UndoMove(action)
+
+	if acting {
+		w := util * reachOthers
+		for i := range node.actions {
+			if i == a {
+				node.regretSum[i] += w * tail * (1 - strategy[a])
+			} else {
+				node.regretSum[i] -= w * tail * strategy[a]
+			}
+		}
+	} else {
+		for i := range node.actions {
+			node.strategySum[i] += reachOthers / sample * strategy[i]
+		}
+	}
+	return util, tail * strategy[a]
+}
+
+// infoSet 返回当前行棋方所在信息集的节点，首次访问时创建。
+func (s *CFRSolver) infoSet() *infoSetNode {
+	key := s.Game.InfoSetKey()
+	node, ok := s.infoSets[key]
+	if !ok {
+		actions := s.Game.Actions()
+		node = &infoSetNode{
+			actions:     actions,
+			regretSum:   make([]float64, len(actions)),
+			strategySum: make([]float64, len(actions)),
+			iteration:   -1,
+		}
+		s.infoSets[key] = node
+	}
+	return node
+}
+
+// currentStrategy 通过遗憾匹配计算信息集的当前策略，同一次遍历内策略保持不变。
+func (s *CFRSolver) currentStrategy(node *infoSetNode) []float64 {
+	if node.iteration == s.traversal {
+		return node.strategy
+	}
+	if node.strategy == nil {
+		node.strategy = make([]float64, len(node.actions))
+	}
+	total := 0.0
+	for i, regret := range node.regretSum {
+		node.strategy[i] = max(regret, 0)
+		total += node.strategy[i]
+	}
+	for i := range node.strategy {
+		if total > 0 {
+			node.strategy[i] /= total
+		} else {
+			node.strategy[i] = 1 / float64(len(node.actions))
+		}
+	}
+	node.iteration = s.traversal
+	return node.strategy
+}
+
+// averageStrategy 返回累计策略归一化后的平均策略，从未累计时返回均匀策略。
+func (n *infoSetNode) averageStrategy() []float64 {
+	average := make([]float64, len(n.actions))
+	total := 0.0
+	for _, w := range n.strategySum {
+		total += w
+	}
+	for i := range average {
+		if total > 0 {
+			average[i] = n.strategySum[i] / total
+		} else {
+			average[i] = 1 / float64(len(n.actions))
+		}
+	}
+	return average
+}
+
+// sampleIndex 按概率分布 probs 采样一个下标。
+func sampleIndex(probs []float64, rng *rand.Rand) int {
+	r := rng.Float64()
+	for i, p := range probs {
+		r -= p
+		if r < 0 {
+			return i
+		}
+	}
+	return len(probs) - 1
+}
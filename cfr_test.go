@@ -0,0 +1,136 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+// kuhnCards 是库恩扑克的三张牌，下标越大牌越大。
+var kuhnCards = []string{"J", "Q", "K"}
+
+// kuhnDeal 是库恩扑克的发牌结果，分别为两名玩家的牌。
+type kuhnDeal [2]int
+
+func (d kuhnDeal) String() string { return kuhnCards[d[0]] + kuhnCards[d[1]] }
+
+// kuhnAction 是库恩扑克的动作：p 为过牌或弃牌，b 为下注或跟注。
+type kuhnAction string
+
+func (a kuhnAction) String() string { return string(a) }
+
+// kuhnPoker 是库恩扑克：每人先下 1 个底注并各发一张牌，玩家 0 先行动，每人最多下注 1 次。
+type kuhnPoker struct {
+	deal    *kuhnDeal
+	history string
+}
+
+func (g *kuhnPoker) IsTerminal() bool {
+	switch g.history {
+	case "pp", "bp", "bb", "pbp", "pbb":
+		return true
+	}
+	return false
+}
+
+func (g *kuhnPoker) Utility(player int) float64 {
+	var value float64
+	switch g.history {
+	case "bp":
+		value = 1
+	case "pbp":
+		value = -1
+	default:
+		value = 1
+		if len(g.history) > 2 || g.history == "bb" {
+			value = 2
+		}
+		if g.deal[0] < g.deal[1] {
+			value = -value
+		}
+	}
+	if player == 1 {
+		return -value
+	}
+	return value
+}
+
+func (g *kuhnPoker) IsChance() bool { return g.deal == nil }
+
+func (g *kuhnPoker) ChanceOutcomes() []ChanceOutcome {
+	var outcomes []ChanceOutcome
+	for a := range kuhnCards {
+		for b := range kuhnCards {
+			if a != b {
+				outcomes = append(outcomes, ChanceOutcome{Move: kuhnDeal{a, b}, Probability: 1.0 / 6})
+			}
+		}
+	}
+	return outcomes
+}
+
+func (g *kuhnPoker) CurrentPlayer() int { return len(g.history) % 2 }
+
+func (g *kuhnPoker) InfoSetKey() string { return kuhnCards[g.deal[g.CurrentPlayer()]] + g.history }
+
+func (g *kuhnPoker) Actions() []Move { return []Move{kuhnAction("p"), kuhnAction("b")} }
+
+func (g *kuhnPoker) Move(move Move) {
+	switch m := move.(type) {
+	case kuhnDeal:
+		g.deal = &m
+	case kuhnAction:
+		g.history += string(m)
+	}
+}
+
+func (g *kuhnPoker) UndoMove(move Move) {
+	if g.history == "" {
+		g.deal = nil
+		return
+	}
+	g.history = g.history[:len(g.history)-1]
+}
+
+// betProbability 返回信息集上平均策略下注（或跟注）的概率。
+func betProbability(t *testing.T, s *CFRSolver, key string) float64 {
+	t.Helper()
+	for _, mp := range s.AverageStrategy(key) {
+		if mp.Move == kuhnAction("b") {
+			return mp.Probability
+		}
+	}
+	t.Fatalf("information set %q was never visited", key)
+	return 0
+}
+
+func TestCFRSolvesKuhnPoker(t *testing.T) {
+	tests := []struct {
+		cfrType    CFRType
+		iterations int
+		epsilon    float64
+	}{
+		{VanillaCFR, 2000, 0.005},
+		{ExternalSamplingCFR, 20000, 0.02},
+		{OutcomeSamplingCFR, 200000, 0.02},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.cfrType), func(t *testing.T) {
+			s := NewCFRSolver(&kuhnPoker{}, tt.cfrType, NewEvaluatorOptions(WithSeed(1)))
+			s.Train(tt.iterations / 100)
+			first := s.Exploitability()
+			s.Train(tt.iterations - tt.iterations/100)
+			last := s.Exploitability()
+			if last >= first || last > tt.epsilon {
+				t.Fatalf("exploitability %v after %d iterations (%v after %d), want decreasing and below %v",
+					last, tt.iterations, first, tt.iterations/100, tt.epsilon)
+			}
+
+			// 纳什均衡中玩家 0 拿 Q 时总是过牌，玩家 1 拿 K 时总是下注或跟注，拿 J 时总是放弃跟注。
+			for key, want := range map[string]float64{"Q": 0, "Kp": 1, "Kb": 1, "Jb": 0} {
+				if got := betProbability(t, s, key); got < want-0.1 || got > want+0.1 {
+					t.Errorf("bet probability at %s is %v, want about %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package gotack
 
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
 // EvalOptions 定义了评估器的配置选项，用于控制评估过程的各个方面。
 type EvalOptions struct {
 	// Board 表示当前游戏的棋盘状态，是评估过程中的基本输入。
@@ -25,9 +31,127 @@ type EvalOptions struct {
 	// 时间单位为秒，默认为 10 秒, 0 表示不限制时间(注意：迭代次数与时间限制不可同时为0)
 	TimeLimit int
 
+	// MaxNodes 表示每次搜索访问节点数的硬上限，默认为 0，表示不限制。所有算法都遵守该上限：
+	// AlphaBeta/PVS、Expectimax、Star1/Star2 与多人算法访问的节点即调用 Move 后进入的局面，设置后改为迭代加深，
+	// 节点数耗尽时中止当前迭代并使用上一次完整迭代的结果，深度 1 的迭代即已耗尽时使用根节点已完成的子节点中最好的走法，一个子节点都没有完成时使用第一个合法走法；
+	// MCTS 类算法为模拟次数；ProofNumber/DFPN 为展开的节点数；CFRSolver 为训练中访问的历史数，以整次迭代为单位停止。
+	// 节点数与机器速度无关，不设置 MoveTime 与 Clock 时迭代加深会忽略 TimeLimit；MCTS 类算法需要另将 TimeLimit 设为 0，
+	// 此时相同的种子在任何机器上得到相同的结果。
+	MaxNodes int64
+
+	// MoveTime 表示每一步固定的思考时间，精度可以小于一秒，设置后优先于 TimeLimit 使用。
+	// 设置 MoveTime 或 Clock 后，AlphaBeta/PVS、Expectimax、Star1/Star2 与多人算法会进行迭代加深，此时 Depth 为最大搜索深度。
+	MoveTime time.Duration
+
+	// Clock 表示对局时钟，设置后由时间管理器根据剩余时间、加时与剩余步数分配本步的软限制与硬限制，
+	// 并优先于 MoveTime 与 TimeLimit 使用。最佳走法不稳定时会延长思考时间，UCT 中某个走法明显占优时会提前停止。
+	Clock *TimeControl
+
+	// ScoreDropMargin 表示迭代加深中根节点行棋方的评估值比上一次迭代下降超过多少时延长思考时间，默认为 0，表示不因评估值下降而延长。
+	ScoreDropMargin float64
+
 	// Thread 表示评估器在评估过程中的线程数，用于并行计算和提高评估的性能。
+	// 目前只有 UCT 的根节点并行使用多个线程，RootSelection 为 RootGumbel 时忽略该值。
 	ThreadNum int
 
+	// Seed 表示搜索使用的随机种子，所有带随机性的算法都从该种子派生随机数，
+	// 相同的种子会得到相同的搜索结果。默认使用当前时间作为种子。
+	Seed int64
+
+	// RandSource 表示注入的随机源，设置后优先于 Seed 使用。
+	// 注意随机源会在多次搜索之间延续状态，且调用者需要保证它不被其他 goroutine 并发使用。
+	RandSource rand.Source
+
+	// RolloutPolicy 表示 UCT 模拟阶段使用的走法选择策略，默认为 RandomRollout。
+	// 策略只在 RolloutDepth 大于 0 时生效，否则直接评估新扩展的节点。
+	RolloutPolicy RolloutPolicy
+
+	// RolloutDepth 表示 MCTS 类算法每次模拟最多走的步数，默认为 0，表示不模拟而直接评估新扩展的节点。
+	// Extra["AheadStep"] 为旧的配置方式，设置后优先于 RolloutDepth 使用。
+	RolloutDepth int
+
+	// WideningK 和 WideningAlpha 控制 MCTS 的渐进展开（progressive widening）：
+	// 访问次数为 N 的节点最多拥有 ⌊K·N^α⌋ 个子节点，走法按启发式评估值从好到坏依次展开。
+	// 默认 K 为 1，α 为 0.5。
+	WideningK     float64
+	WideningAlpha float64
+
+	// MaxChildren 限制 MCTS 中单个节点最多展开的子节点数，0 表示不限制。
+	MaxChildren int
+
+	// ProgressiveBias 表示 MCTS 渐进偏置（progressive bias）的权重 W。
+	// 选择子节点时在 UCT 值上额外加上 W·H/(n+1)，H 为走法的启发式评估值，n 为子节点访问次数。
+	// 默认为 0，表示不使用渐进偏置。
+	ProgressiveBias float64
+
+	// EvalLowerBound 和 EvalUpperBound 表示 EvaluateFunc 返回值的下界与上界，Star1/Star2 依赖它们在机会节点剪枝。
+	// UCT 与 ISMCTS 的模拟遇到胜负结果时分别以上界与下界作为胜负的奖励，此时必须设置为有限值。
+	// 默认为负无穷与正无穷，此时不会发生机会节点剪枝。
+	EvalLowerBound float64
+	EvalUpperBound float64
+
+	// History 表示当前局面之前对局中依次出现过的局面哈希（Board.Hash()），用于检测对局中的重复局面。
+	History []uint64
+
+	// SearchRepetitions 表示同一局面在搜索路径（含根局面）中累计出现多少次即判为和棋，例如 2 表示二次重复。
+	// GameRepetitions 表示同一局面连同对局历史累计出现多少次即判为和棋，例如 3 表示三次重复。
+	// 两者默认均为 0，表示不检测重复。启用后要求 Hash 能够区分行棋方。
+	SearchRepetitions int
+	GameRepetitions   int
+
+	// Contempt 表示根节点行棋方对和棋的厌恶程度，和棋以根节点行棋方视角记为 -Contempt，默认为 0。
+	Contempt float64
+
+	// RootSelection 表示 MCTS 在根节点选择动作的方式，默认为 RootUCB。
+	// RootGumbel 适合只允许 50~200 次模拟的场景，此时 Iterations 为 0 则使用 200 次模拟。
+	// RootGumbel 总是单线程搜索，忽略 ThreadNum；根局面为机会节点时 Search 返回 ErrChanceRoot。
+	RootSelection RootSelectionType
+
+	// GumbelK 表示 Gumbel 根节点选择中参与 Sequential Halving 的候选走法数，默认为 16。
+	GumbelK int
+
+	// GumbelCVisit 和 GumbelCScale 控制 Gumbel 根节点选择中价值项 σ(q̂) 的尺度，默认分别为 50 和 1。
+	GumbelCVisit float64
+	GumbelCScale float64
+
+	// SimultaneousSelection 表示同时行动博弈中每个玩家独立选择走法的方式，默认为 DecoupledUCT。
+	SimultaneousSelection SimultaneousSelectionType
+
+	// Exp3Gamma 表示 Exp3 选择中均匀探索的比例 γ，取值范围 (0, 1]，默认为 0.1。
+	Exp3Gamma float64
+
+	// MoveEvalThreads 表示 MCTS 扩展节点时并行预评估走法的 goroutine 数，默认为 1，即串行评估。
+	MoveEvalThreads int
+
+	// NoMoves 表示局面未结束却没有合法走法时的处理方式，默认为 NoMovesEvaluate，即把局面当作叶节点评估；
+	// 允许停一手的棋盘需要设置为 NoMovesPass 并实现 PassBoard。
+	NoMoves NoMovesRule
+
+	// Tablebase 表示搜索中查询的残局库，命中的非根节点直接使用残局库中的精确结果，默认为 nil。
+	// 目前 AlphaBeta、PVS 与 UCT 会查询残局库。
+	Tablebase *Tablebase
+
+	// OpeningBook 表示 GetBestMove 优先查询的开局库，根局面在库中且有合法的库走法时不再进行搜索，默认为 nil。
+	OpeningBook *OpeningBook
+
+	// BookSelection 表示从开局库中选择走法的方式，默认为 BookWeighted。
+	BookSelection BookSelectionType
+
+	// MultiPV 表示需要给出评估值与主要变例的根节点候选走法数，结果保存在 SearchResult.Lines 中。
+	// 默认为 0，表示只搜索最佳走法。AlphaBeta/PVS 会因此多搜索 MultiPV-1 次，UCT 不增加搜索量。
+	MultiPV int
+
+	// MoveSelection 表示搜索结束后选出最终走法的方式，默认为 SelectTies，即返回全部并列的最佳走法。
+	// 选择 SelectRandomTie、SelectMargin 或 SelectSoftmax 时 AlphaBeta/PVS 会对全部根节点走法进行 Multi-PV 搜索。
+	// SelectionMargin 为 SelectMargin 的评估值差距，SelectionTemperature 为 SelectSoftmax 的温度，
+	// 也是 SelectMargin 中候选走法按 softmax 加权的温度，0 表示均匀选择。随机选择使用 Seed 派生的随机数。
+	MoveSelection        MoveSelectionType
+	SelectionMargin      float64
+	SelectionTemperature float64
+
+	// Skill 表示对搜索结果的削弱，用于提供不同的难度，默认为 nil，表示以全部棋力搜索。
+	Skill *SkillLevel
+
 	// Extra 提供了一个映射，用于存储评估过程中可能需要的任何额外信息或自定义数据。
 	// 这使得 EvalOptions 可以灵活地适应各种额外的需求，而无需修改结构体定义。
 	Extra map[string]interface{}
@@ -39,14 +163,25 @@ type EvalOption func(*EvalOptions)
 // 可以通过传入不同的 EvalOption 配置函数来自定义配置项，例如 Depth 或 Board。
 func NewEvaluatorOptions(opts ...EvalOption) *EvalOptions {
 	opt := &EvalOptions{
-		Depth:       1,
-		Step:        1,
-		Iterations:  0,
-		TimeLimit:   10,
-		IsDetail:    false,
-		IsMaxPlayer: true,
-		ThreadNum:   1,
-		Extra:       make(map[string]interface{}),
+		Depth:           1,
+		Step:            1,
+		Iterations:      0,
+		TimeLimit:       10,
+		IsDetail:        false,
+		IsMaxPlayer:     true,
+		ThreadNum:       1,
+		Seed:            time.Now().UnixNano(),
+		EvalLowerBound:  math.Inf(-1),
+		EvalUpperBound:  math.Inf(1),
+		RolloutPolicy:   RandomRollout{},
+		WideningK:       1,
+		WideningAlpha:   0.5,
+		MoveEvalThreads: 1,
+		GumbelK:         16,
+		GumbelCVisit:    50,
+		GumbelCScale:    1,
+		Exp3Gamma:       0.1,
+		Extra:           make(map[string]interface{}),
 	}
 	for _, o := range opts {
 		o(opt)
@@ -114,6 +249,191 @@ func WithThreadNum(threadNum int) EvalOption {
 	}
 }
 
+// WithSeed 配置 EvalOptions 的 Seed 属性，用于获得可复现的搜索结果。
+func WithSeed(seed int64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.Seed = seed
+	}
+}
+
+// WithRandSource 配置 EvalOptions 的 RandSource 属性，注入自定义的随机源。
+func WithRandSource(source rand.Source) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.RandSource = source
+	}
+}
+
+// WithRolloutPolicy 配置 EvalOptions 的 RolloutPolicy 属性，用于选择 UCT 模拟阶段的走法策略。
+// 默认的 RolloutDepth 为 0，不会进行模拟，需要同时通过 WithRolloutDepth 设置模拟步数策略才会生效。
+func WithRolloutPolicy(policy RolloutPolicy) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.RolloutPolicy = policy
+	}
+}
+
+// WithRolloutDepth 配置 EvalOptions 的 RolloutDepth 属性，设置 MCTS 类算法每次模拟最多走的步数。
+func WithRolloutDepth(depth int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.RolloutDepth = depth
+	}
+}
+
+// WithProgressiveWidening 配置 MCTS 的渐进展开参数，访问次数为 N 的节点最多拥有 ⌊k·N^alpha⌋ 个子节点。
+func WithProgressiveWidening(k, alpha float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.WideningK = k
+		opts.WideningAlpha = alpha
+	}
+}
+
+// WithMaxChildren 配置 EvalOptions 的 MaxChildren 属性，限制 MCTS 中单个节点的子节点数。
+func WithMaxChildren(maxChildren int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MaxChildren = maxChildren
+	}
+}
+
+// WithProgressiveBias 配置 EvalOptions 的 ProgressiveBias 属性，设置 MCTS 渐进偏置的权重。
+func WithProgressiveBias(weight float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.ProgressiveBias = weight
+	}
+}
+
+// WithEvalBounds 配置 EvaluateFunc 返回值的下界与上界，用于 Star1/Star2 在机会节点剪枝以及 MCTS 中胜负结果的奖励。
+func WithEvalBounds(lower, upper float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.EvalLowerBound = lower
+		opts.EvalUpperBound = upper
+	}
+}
+
+// WithHistory 配置 EvalOptions 的 History 属性，提供当前局面之前的对局历史局面哈希。
+func WithHistory(history []uint64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.History = history
+	}
+}
+
+// WithRepetition 配置重复局面的判和规则：搜索路径中出现 searchFold 次或连同对局历史出现 gameFold 次即判为和棋。
+func WithRepetition(searchFold, gameFold int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.SearchRepetitions = searchFold
+		opts.GameRepetitions = gameFold
+	}
+}
+
+// WithContempt 配置 EvalOptions 的 Contempt 属性，设置根节点行棋方对和棋的厌恶程度。
+func WithContempt(contempt float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.Contempt = contempt
+	}
+}
+
+// WithRootSelection 配置 EvalOptions 的 RootSelection 属性，选择 MCTS 根节点的动作选择方式。
+func WithRootSelection(selection RootSelectionType) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.RootSelection = selection
+	}
+}
+
+// WithGumbel 配置 Gumbel 根节点选择的候选数 k 以及价值项尺度 cVisit 和 cScale。
+func WithGumbel(k int, cVisit, cScale float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.GumbelK = k
+		opts.GumbelCVisit = cVisit
+		opts.GumbelCScale = cScale
+	}
+}
+
+// WithSimultaneousSelection 配置同时行动博弈中玩家选择走法的方式，gamma 为 Exp3 的探索比例。
+func WithSimultaneousSelection(selection SimultaneousSelectionType, gamma float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.SimultaneousSelection = selection
+		opts.Exp3Gamma = gamma
+	}
+}
+
+// WithMoveEvalThreads 配置 EvalOptions 的 MoveEvalThreads 属性，控制 MCTS 扩展时并行预评估走法的 goroutine 数。
+func WithMoveEvalThreads(threads int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MoveEvalThreads = threads
+	}
+}
+
+// WithNoMovesRule 配置 EvalOptions 的 NoMoves 属性，设置没有合法走法时的处理方式。
+func WithNoMovesRule(rule NoMovesRule) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.NoMoves = rule
+	}
+}
+
+// WithTablebase 配置 EvalOptions 的 Tablebase 属性，设置搜索中查询的残局库。
+func WithTablebase(tb *Tablebase) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.Tablebase = tb
+	}
+}
+
+// WithOpeningBook 配置 EvalOptions 的 OpeningBook 与 BookSelection 属性，设置开局库及其走法选择方式。
+func WithOpeningBook(book *OpeningBook, selection BookSelectionType) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.OpeningBook = book
+		opts.BookSelection = selection
+	}
+}
+
+// WithMoveTime 配置 EvalOptions 的 MoveTime 属性，设置每一步固定的思考时间。
+func WithMoveTime(moveTime time.Duration) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MoveTime = moveTime
+	}
+}
+
+// WithMaxNodes 配置 EvalOptions 的 MaxNodes 属性，设置每次搜索访问节点数的硬上限。
+func WithMaxNodes(nodes int64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MaxNodes = nodes
+	}
+}
+
+// WithClock 配置 EvalOptions 的 Clock 属性，根据对局时钟的剩余时间、每步加时与剩余步数分配思考时间。
+func WithClock(remaining, increment time.Duration, movesToGo int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.Clock = &TimeControl{Remaining: remaining, Increment: increment, MovesToGo: movesToGo}
+	}
+}
+
+// WithScoreDropMargin 配置 EvalOptions 的 ScoreDropMargin 属性，设置评估值下降多少时延长思考时间。
+func WithScoreDropMargin(margin float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.ScoreDropMargin = margin
+	}
+}
+
+// WithMultiPV 配置 EvalOptions 的 MultiPV 属性，设置需要给出评估值与主要变例的根节点候选走法数。
+func WithMultiPV(count int) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MultiPV = count
+	}
+}
+
+// WithMoveSelection 配置搜索结束后选出最终走法的方式，margin 与 temperature 分别为评估值差距与 softmax 温度。
+func WithMoveSelection(selection MoveSelectionType, margin, temperature float64) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.MoveSelection = selection
+		opts.SelectionMargin = margin
+		opts.SelectionTemperature = temperature
+	}
+}
+
+// WithSkill 配置 EvalOptions 的 Skill 属性，按 skill 削弱搜索的深度、节点数与走法选择。
+func WithSkill(skill SkillLevel) EvalOption {
+	return func(opts *EvalOptions) {
+		opts.Skill = &skill
+	}
+}
+
 // WithIsMaxPlayer 配置 EvalOptions 的 IsMaxPlayer 属性，指示当前评估的玩家是否是最大化玩家。
 func WithIsMaxPlayer(isMaxPlayer bool) EvalOption {
 	return func(opts *EvalOptions) {
@@ -3,6 +3,8 @@ package gotack
 import (
 	"fmt"
 	"math"
+	"math/rand"
+	"sync/atomic"
 )
 
 type GameTreeType int
@@ -11,6 +13,16 @@ const (
 	AlphaBeta GameTreeType = iota // 使用 Alpha-Beta  算法
 	PVS                           // 使用 PVS 剪枝算法
 	UCT
+	Expectimax      // 使用 Expectimax 算法，支持实现了 ChanceBoard 的含机会节点的棋盘
+	Star1           // 使用 Star1 剪枝的 *-Minimax 算法，需要通过 WithEvalBounds 设置评估值界限
+	Star2           // 使用带试探阶段的 Star2 剪枝的 *-Minimax 算法
+	MaxN            // 使用多人博弈的 Max^n 算法，棋盘需要实现 MultiPlayerBoard
+	Paranoid        // 使用多人博弈的 Paranoid 算法，棋盘需要实现 MultiPlayerBoard
+	BRS             // 使用多人博弈的 Best-Reply Search 算法，棋盘需要实现 BestReplyBoard
+	ISMCTS          // 使用单观察者信息集蒙特卡洛树搜索，棋盘需要实现 Determinizer
+	SimultaneousUCT // 使用同时行动博弈的解耦 MCTS（DUCT 或 Exp3），棋盘需要实现 SimultaneousBoard
+	ProofNumber     // 使用最佳优先的证明数搜索证明根节点行棋方能否强制取胜
+	DFPN            // 使用带置换表的深度优先证明数搜索（df-pn）证明根节点行棋方能否强制取胜
 	// 可以添加更多的算法类型
 )
 
@@ -20,6 +32,45 @@ type Evaluator struct {
 	Board       Board
 	Depth       int
 	BestMoves   []Move
+
+	// Result 是最近一次 GetBestMove 的搜索结果。
+	Result *SearchResult
+
+	// MixedStrategy 是 SimultaneousUCT 搜索得到的根节点混合策略。
+	MixedStrategy []MoveProbability
+
+	rng         *rand.Rand // 本次搜索的随机数生成器
+	multiPlayer bool       // 棋盘是否实现了 MultiPlayerBoard
+	rootPlayer  int        // 多人棋盘中根节点行棋方的编号
+
+	fixedRootPlayer bool // rootPlayer 由调用者指定而非取自根局面，此时根局面可能轮到其他玩家走棋
+
+	pathHashes    []uint64       // 当前搜索路径上各局面的哈希
+	treePath      map[uint64]int // UCT 本次迭代选择路径上各祖先局面哈希的出现次数
+	historyCounts map[uint64]int // 对局历史中各局面出现的次数
+
+	err       error        // 搜索过程中遇到的第一个错误
+	tm        *timeManager // 本次搜索的时间管理器
+	stopped   bool         // 迭代加深的当前迭代是否因时间或节点数耗尽而中止
+	deepening bool         // 是否正在进行迭代加深，此时允许中止当前迭代
+
+	stopFlag *atomic.Bool     // 外部停止搜索的标志，由后台搜索与并行的副本共享
+	infinite bool             // 是否为后台思考或分析，此时搜索不受时间限制，直到 stopFlag 被设置
+	onInfo   func(SearchInfo) // 分析模式下接收搜索进度的回调
+	nodes    int64            // 本次搜索访问的节点数（MCTS 为模拟次数）
+
+	lastDepth int   // 迭代加深最后一次完整迭代的深度
+	tree      *Node // 最近一次 MCTS 搜索的根节点
+
+	pvTable [][]Move // AlphaBeta/PVS 中每一层的主要变例
+	pv      []Move   // 最近一次搜索的主要变例
+
+	excluded map[string]bool // Multi-PV 搜索中根节点需要排除的走法
+	lines    []PVLine        // 最近一次 Multi-PV 搜索的结果
+	multiPV  int             // 本次搜索实际进行 Multi-PV 的走法数，SkillLevel 或 MoveSelection 需要时为全部根节点走法
+
+	nodeLimit int64       // 本次搜索的节点数上限，0 表示不限制
+	stats     SearchStats // 本次搜索的统计信息，节点数与用时在生成结果时补全
 }
 
 // NewEvaluator 创建并初始化一个 Evaluator 对象。
@@ -69,25 +120,137 @@ func NewEvaluator(treeType GameTreeType, opts *EvalOptions) *Evaluator {
 //   - Other Algorithms: 可以添加其他类型的博弈树算法，每种类型需根据其特定逻辑执行并返回最佳移动。
 //   - default: 如果树类型不被支持，打印错误消息并返回一个默认的 Move 结构。
 //
+// 搜索出错时同样打印错误消息并返回空切片，需要区分错误类型时请使用 Search。
+//
 // 示例用法:
 //
 //	board := // 初始化或获取当前棋盘状态
 //	evaluator := // 创建并初始化 Evaluator 实例
 //	bestMove := evaluator.GetBestMove(board) // 使用 bestMove 进行下一步操作
 func (e *Evaluator) GetBestMove() []Move {
+	result, err := e.Search()
+	if err != nil {
+		fmt.Println(err)
+		return []Move{}
+	}
+	return result.BestMoves
+}
+
+// Search 在当前棋盘上运行 TreeType 指定的搜索算法，并返回搜索结果。
+//
+// 返回值:
+//   - *SearchResult: 搜索结果，同时保存在 e.Result 中。设置了开局库且根局面在库中时直接返回库走法；
+//     根局面没有合法走法且按 NoMovesEvaluate、NoMovesLoss 或 NoMovesDraw 处理时，结果只包含评估值而没有最佳走法。
+//   - error: 棋盘没有实现算法需要的接口时返回 ErrUnsupportedBoard，不支持的算法类型返回 ErrUnsupportedTreeType，
+//     搜索遇到无法处理的无合法走法局面时返回 ErrNoLegalMoves，UCT 与 ISMCTS 可能得到胜负结果却没有设置评估值界限时返回 ErrEvalBoundsRequired，
+//     Gumbel 根节点选择的根局面为机会节点时返回 ErrChanceRoot。
+func (e *Evaluator) Search() (*SearchResult, error) {
 	var bestMoves []Move
 	var value float64
+	e.rng = e.EvalOptions.newRand()
+	e.err = nil
+	e.tm = e.newTimeManager()
+	e.stopped = false
+	e.nodes = 0
+	e.stats = SearchStats{}
+	e.lastDepth = 0
+	e.tree = nil
+	e.pv = nil
+	e.lines = nil
+	e.MixedStrategy = nil
+	e.multiPV = e.EvalOptions.MultiPV
+	e.nodeLimit = e.EvalOptions.nodeBudget()
+	if skill := e.EvalOptions.Skill; skill != nil {
+		if skill.MaxDepth > 0 && skill.MaxDepth < e.EvalOptions.Depth {
+			defer func(optsDepth, depth int) {
+				e.EvalOptions.Depth, e.Depth = optsDepth, depth
+			}(e.EvalOptions.Depth, e.Depth)
+			e.EvalOptions.Depth, e.Depth = skill.MaxDepth, skill.MaxDepth
+		}
+	}
+	if e.EvalOptions.Skill.needsRootScores() || e.EvalOptions.MoveSelection.needsRootScores() {
+		e.multiPV = math.MaxInt32
+	}
+	e.pvTable = make([][]Move, max(e.Depth, e.EvalOptions.Depth)+1)
+	e.initPlayers()
+	e.initRepetition()
+	if move := e.bookMove(); move != nil {
+		e.BestMoves = []Move{move}
+		e.Result = &SearchResult{BestMoves: e.BestMoves, FromBook: true}
+		return e.Result, nil
+	}
+	if rootValue, ok := e.rootWithoutMoves(); ok {
+		if e.err != nil {
+			return nil, e.err
+		}
+		e.Result = e.newSearchResult(rootValue, nil)
+		return e.Result, nil
+	}
 	switch e.TreeType {
-	case AlphaBeta:
-		value, bestMoves = e.alphaBeta(e.EvalOptions.Depth, -math.MaxFloat64, math.MaxFloat64, e.EvalOptions.IsMaxPlayer, e.EvalOptions)
-	case PVS:
-		value, bestMoves = e.pvs(e.EvalOptions.Depth, -math.MaxFloat64, math.MaxFloat64, e.EvalOptions.IsMaxPlayer, e.EvalOptions)
+	case AlphaBeta, PVS:
+		search := e.alphaBeta
+		if e.TreeType == PVS {
+			search = e.pvs
+		}
+		run := func(depth int) (float64, []Move) {
+			return search(depth, -math.MaxFloat64, math.MaxFloat64, e.EvalOptions.IsMaxPlayer, e.EvalOptions)
+		}
+		if e.multiPV > 1 {
+			single := run
+			run = func(depth int) (float64, []Move) {
+				return e.multiPVSearch(single, depth)
+			}
+		}
+		value, bestMoves = e.depthSearch(run, e.tm.managed)
 	case UCT:
+		if err := e.checkRewardBounds(); err != nil {
+			return nil, err
+		}
 		value, bestMoves = e.uct(e.EvalOptions)
+		if e.EvalOptions.MultiPV > 1 {
+			e.lines = e.treeLines(e.tree)
+		}
+	case Expectimax:
+		value, bestMoves = e.depthSearch(func(depth int) (float64, []Move) {
+			return e.expectimax(depth, e.EvalOptions.IsMaxPlayer, e.EvalOptions)
+		}, e.tm.managed)
+	case Star1, Star2:
+		value, bestMoves = e.depthSearch(func(depth int) (float64, []Move) {
+			return e.star(depth, -math.MaxFloat64, math.MaxFloat64, e.EvalOptions.IsMaxPlayer, e.TreeType == Star2, e.EvalOptions)
+		}, e.tm.managed)
+	case ISMCTS:
+		if _, ok := e.Board.(Determinizer); !ok {
+			return nil, fmt.Errorf("%w: Determinizer is required", ErrUnsupportedBoard)
+		}
+		if err := e.checkRewardBounds(); err != nil {
+			return nil, err
+		}
+		value, bestMoves = e.ismcts(e.EvalOptions)
+	case SimultaneousUCT:
+		if _, ok := e.Board.(SimultaneousBoard); !ok {
+			return nil, fmt.Errorf("%w: SimultaneousBoard is required", ErrUnsupportedBoard)
+		}
+		value, bestMoves = e.simultaneousUCT(e.EvalOptions)
+	case ProofNumber, DFPN:
+		result := e.Solve()
+		value = result.value(e.EvalOptions.IsMaxPlayer)
+		if result.Move != nil {
+			bestMoves = []Move{result.Move}
+		}
+	case MaxN, Paranoid, BRS:
+		if !e.supportsMultiPlayer() {
+			return nil, fmt.Errorf("%w: multi-player search is not supported", ErrUnsupportedBoard)
+		}
+		value, bestMoves = e.depthSearch(e.multiPlayerSearch, e.tm.managed)
 	default:
-		fmt.Println("Unsupported tree type")
-		return []Move{}
+		return nil, ErrUnsupportedTreeType
 	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	value, bestMoves = e.selectMove(value, bestMoves)
+	value, bestMoves = e.weaken(value, bestMoves)
+	e.Result = e.newSearchResult(value, bestMoves)
 	if e.EvalOptions.IsDetail {
 		// 使用基本的 ASCII 字符格式化输出详细信息
 		fmt.Println("+-----------------+----------------------------------+")
@@ -103,6 +266,37 @@ func (e *Evaluator) GetBestMove() []Move {
 		fmt.Println("+-----------------+----------------------------------+")
 		fmt.Printf("| %-15s | %-32f |\n", "Values:", value)
 		fmt.Println("+-----------------+----------------------------------+")
+		if e.Result.MateIn > 0 {
+			fmt.Printf("| %-15s | %-32s |\n", "Mate:", fmt.Sprintf("win in %d plies", e.Result.MateIn))
+			fmt.Println("+-----------------+----------------------------------+")
+		} else if e.Result.MateIn < 0 {
+			fmt.Printf("| %-15s | %-32s |\n", "Mate:", fmt.Sprintf("loss in %d plies", -e.Result.MateIn))
+			fmt.Println("+-----------------+----------------------------------+")
+		}
+		stats := e.Result.Stats
+		for _, row := range []struct {
+			name  string
+			value any
+		}{
+			{"Nodes:", stats.Nodes},
+			{"NPS:", stats.NPS},
+			{"Leaf Evals:", stats.LeafEvals},
+			{"Move Gens:", stats.MoveGenerations},
+			{"Cutoffs:", fmt.Sprintf("%d (first move %.1f%%)", stats.Cutoffs, 100*stats.FirstMoveCutoffRate())},
+			{"TT Hits:", stats.TTHits},
+			{"TB Hits:", stats.TablebaseHits},
+			{"Re-searches:", stats.ReSearches},
+			{"Playouts:", stats.Playouts},
+			{"Tree Size:", stats.TreeSize},
+			{"Max Depth:", stats.MaxDepth},
+		} {
+			fmt.Printf("| %-15s | %-32v |\n", row.name, row.value)
+			fmt.Println("+-----------------+----------------------------------+")
+		}
+		for i, line := range e.lines {
+			fmt.Printf("| %-15s | %-32s |\n", fmt.Sprintf("PV %d:", i+1), fmt.Sprintf("%v %f", line.Move, line.Value))
+			fmt.Println("+-----------------+----------------------------------+")
+		}
 		fmt.Print("| Best Moves     | ")
 
 		for i, move := range bestMoves {
@@ -128,5 +322,5 @@ func (e *Evaluator) GetBestMove() []Move {
 			fmt.Println("+-----------------+----------------------------------+")
 		}
 	}
-	return bestMoves
+	return e.Result, nil
 }
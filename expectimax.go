@@ -0,0 +1,270 @@
+package gotack
+
+import (
+	"math"
+	"math/rand"
+)
+
+// chanceOutcomes 若棋盘实现了 ChanceBoard 且当前为机会节点，返回全部随机结果与 true。
+func chanceOutcomes(board Board) ([]ChanceOutcome, bool) {
+	if cb, ok := board.(ChanceBoard); ok && cb.IsChanceNode() {
+		return cb.ChanceOutcomes(), true
+	}
+	return nil, false
+}
+
+// sampleOutcome 按概率从随机结果中采样一个，并返回对应的走法。
+func sampleOutcome(outcomes []ChanceOutcome, rng *rand.Rand) Move {
+	r := rng.Float64()
+	for _, outcome := range outcomes {
+		r -= outcome.Probability
+		if r < 0 {
+			return outcome.Move
+		}
+	}
+	return outcomes[len(outcomes)-1].Move
+}
+
+// expectimax 在 Alpha-Beta 的基础上处理机会节点：机会节点的值为各随机结果值按概率加权的期望。
+// 玩家节点不做剪枝，机会节点不消耗搜索深度。
+func (e *Evaluator) expectimax(depth int, isMaximizingPlayer bool, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	e.reachDepth(e.Depth - depth)
+	if depth == 0 || e.Board.IsGameOver() {
+		opts.Extra["depth"] = e.Depth - depth
+		return e.evaluateLeaf(isMaximizingPlayer, e.Depth-depth, opts), nil
+	}
+
+	if outcomes, ok := chanceOutcomes(e.Board); ok {
+		expected := 0.0
+		for _, outcome := range outcomes {
+			if e.stopped {
+				break
+			}
+			e.Board.Move(outcome.Move)
+			eval, _ := e.expectimax(depth, isMaximizingPlayer, opts)
+			e.Board.UndoMove(outcome.Move)
+			expected += outcome.Probability * eval
+		}
+		return expected, nil
+	}
+
+	moves := e.legalMoves(e.Board, isMaximizingPlayer)
+	if len(moves) == 0 {
+		return e.noMovesValue(isMaximizingPlayer, e.Depth-depth), nil
+	}
+
+	var bestMoves []Move
+	bestEval := math.Inf(1)
+	if isMaximizingPlayer {
+		bestEval = math.Inf(-1)
+	}
+	for _, move := range moves {
+		e.Board.Move(move)
+		eval, _ := e.expectimax(depth-1, !isMaximizingPlayer, opts)
+		e.Board.UndoMove(move)
+		if e.stopped {
+			break
+		}
+
+		if (isMaximizingPlayer && eval > bestEval) || (!isMaximizingPlayer && eval < bestEval) {
+			bestEval = eval
+			bestMoves = []Move{move}
+		} else if eval == bestEval {
+			bestMoves = append(bestMoves, move)
+		}
+	}
+	if depth == e.Depth {
+		e.BestMoves = bestMoves // 只在顶层更新 BestMoves
+	}
+	return bestEval, bestMoves
+}
+
+// star 实现 Ballard 的 *-Minimax 剪枝（Star1，probe 为 true 时为 Star2）。
+// 玩家节点按 Alpha-Beta 搜索；机会节点利用评估值的上下界 EvalLowerBound/EvalUpperBound
+// 为每个随机结果计算收窄的搜索窗口，一旦剩余结果无论取何值都无法使期望落入 (alpha, beta) 即剪枝。
+// Star2 在正式搜索前先对每个随机结果只试探第一个走法，得到更紧的单侧界限后再进行 Star1 搜索。
+// 评估值的界限越紧，剪枝效果越好；界限为无穷时结果与 Expectimax 相同。可能出现胜负评估值时界限见 starBounds。
+func (e *Evaluator) star(depth int, alpha, beta float64, isMaximizingPlayer bool, probe bool, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	e.reachDepth(e.Depth - depth)
+	if depth == 0 || e.Board.IsGameOver() {
+		opts.Extra["depth"] = e.Depth - depth
+		return e.evaluateLeaf(isMaximizingPlayer, e.Depth-depth, opts), nil
+	}
+
+	if outcomes, ok := chanceOutcomes(e.Board); ok {
+		return e.starChance(depth, alpha, beta, isMaximizingPlayer, probe, outcomes, opts), nil
+	}
+
+	moves := e.legalMoves(e.Board, isMaximizingPlayer)
+	if len(moves) == 0 {
+		return e.noMovesValue(isMaximizingPlayer, e.Depth-depth), nil
+	}
+
+	var bestMoves []Move
+	var eval float64
+	if isMaximizingPlayer {
+		maxEval := math.Inf(-1)
+		for i, move := range moves {
+			e.Board.Move(move)
+			eval, _ = e.star(depth-1, alpha, beta, false, probe, opts)
+			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
+
+			if eval > maxEval {
+				maxEval = eval
+				bestMoves = []Move{move}
+			} else if eval == maxEval {
+				bestMoves = append(bestMoves, move)
+			}
+			alpha = math.Max(alpha, eval)
+			if beta <= alpha {
+				e.cutoff(i)
+				break
+			}
+		}
+		if depth == e.Depth {
+			e.BestMoves = bestMoves
+		}
+		return maxEval, bestMoves
+	} else {
+		minEval := math.Inf(1)
+		for i, move := range moves {
+			e.Board.Move(move)
+			eval, _ = e.star(depth-1, alpha, beta, true, probe, opts)
+			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
+
+			if eval < minEval {
+				minEval = eval
+				bestMoves = []Move{move}
+			} else if eval == minEval {
+				bestMoves = append(bestMoves, move)
+			}
+			beta = math.Min(beta, eval)
+			if beta <= alpha {
+				e.cutoff(i)
+				break
+			}
+		}
+		if depth == e.Depth {
+			e.BestMoves = bestMoves // 只在顶层更新 BestMoves
+		}
+		return minEval, bestMoves
+	}
+}
+
+// starChance 计算机会节点的值。返回值小于等于 alpha 时为上界，大于等于 beta 时为下界，否则为精确值。
+func (e *Evaluator) starChance(depth int, alpha, beta float64, isMaximizingPlayer bool, probe bool, outcomes []ChanceOutcome, opts *EvalOptions) float64 {
+	// lower[i]、upper[i] 是第 i 个随机结果取值的已知界限
+	low, high := e.starBounds(opts)
+	lower := make([]float64, len(outcomes))
+	upper := make([]float64, len(outcomes))
+	for i := range outcomes {
+		lower[i] = low
+		upper[i] = high
+	}
+
+	if probe {
+		for i, outcome := range outcomes {
+			if e.stopped {
+				break
+			}
+			e.Board.Move(outcome.Move)
+			low, high := e.starProbe(depth, alpha, beta, isMaximizingPlayer, opts)
+			e.Board.UndoMove(outcome.Move)
+			lower[i] = math.Max(lower[i], low)
+			upper[i] = math.Min(upper[i], high)
+		}
+		if low := weightedSum(outcomes, lower, 0); low >= beta {
+			return low
+		}
+		if high := weightedSum(outcomes, upper, 0); high <= alpha {
+			return high
+		}
+	}
+
+	sum := 0.0
+	for i, outcome := range outcomes {
+		p := outcome.Probability
+		if p <= 0 {
+			continue
+		}
+		restHigh := weightedSum(outcomes, upper, i+1)
+		restLow := weightedSum(outcomes, lower, i+1)
+		childAlpha := (alpha - sum - restHigh) / p
+		childBeta := (beta - sum - restLow) / p
+
+		e.Board.Move(outcome.Move)
+		value, _ := e.star(depth, math.Max(lower[i], childAlpha), math.Min(upper[i], childBeta), isMaximizingPlayer, probe, opts)
+		e.Board.UndoMove(outcome.Move)
+
+		if value <= childAlpha {
+			return sum + p*value + restHigh
+		}
+		if value >= childBeta {
+			return sum + p*value + restLow
+		}
+		sum += p * value
+	}
+	return sum
+}
+
+// starProbe 对机会结果之后的玩家节点只搜索第一个走法，返回该节点值的下界与上界。
+// 最大化玩家的一个走法给出下界，最小化玩家的一个走法给出上界；叶节点返回精确值。
+// 由于搜索是 fail-soft 的，试探结果落在窗口外侧时只提供相反方向的界限，此时不更新界限。
+func (e *Evaluator) starProbe(depth int, alpha, beta float64, isMaximizingPlayer bool, opts *EvalOptions) (float64, float64) {
+	lower, upper := e.starBounds(opts)
+	if depth == 0 || e.Board.IsGameOver() {
+		value, _ := e.star(depth, alpha, beta, isMaximizingPlayer, true, opts)
+		return value, value
+	}
+	if _, ok := chanceOutcomes(e.Board); ok {
+		return lower, upper
+	}
+	moves := e.legalMoves(e.Board, isMaximizingPlayer)
+	if len(moves) == 0 {
+		return lower, upper
+	}
+	e.Board.Move(moves[0])
+	value, _ := e.star(depth-1, alpha, beta, !isMaximizingPlayer, true, opts)
+	e.Board.UndoMove(moves[0])
+	if isMaximizingPlayer && value > alpha {
+		lower = value
+	} else if !isMaximizingPlayer && value < beta {
+		upper = value
+	}
+	return lower, upper
+}
+
+// starBounds 返回 Star1/Star2 使用的评估值界限。棋盘实现了 ResultBoard 或 NoMoves 规则为 NoMovesLoss 时，
+// 叶节点可能得到超出 EvalLowerBound/EvalUpperBound 的胜负评估值，此时界限放宽到 ±MateScore 以保证剪枝正确。
+func (e *Evaluator) starBounds(opts *EvalOptions) (float64, float64) {
+	lower, upper := opts.EvalLowerBound, opts.EvalUpperBound
+	if _, ok := e.Board.(ResultBoard); ok || opts.NoMoves == NoMovesLoss {
+		lower, upper = math.Min(lower, -MateScore), math.Max(upper, MateScore)
+	}
+	return lower, upper
+}
+
+// weightedSum 返回从下标 from 开始各随机结果概率与 values 的加权和。
+func weightedSum(outcomes []ChanceOutcome, values []float64, from int) float64 {
+	sum := 0.0
+	for i := from; i < len(outcomes); i++ {
+		if outcomes[i].Probability > 0 {
+			sum += outcomes[i].Probability * values[i]
+		}
+	}
+	return sum
+}
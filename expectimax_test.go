@@ -0,0 +1,205 @@
+package gotack
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// diceMove 是 diceBoard 上骰子的结果，值为 diceValues 的下标。
+type diceMove int
+
+func (m diceMove) String() string { return "d" + strconv.Itoa(int(m)) }
+
+// diceValues 与 diceProbabilities 是每次掷骰加到总分上的值及其概率。
+var (
+	diceValues        = []float64{-5, 0, 5}
+	diceProbabilities = []float64{0.25, 0.5, 0.25}
+)
+
+// diceBoard 是一个带机会节点的确定性测试棋盘：玩家轮流从 scores 中选择一个下标，每次选择之后掷一次骰子，
+// 总分为所选分数与骰子值之和。共进行 rounds 轮，掷骰后总分的绝对值达到 knockout 时提前结束。
+// ordered 为 true 时 GetAllMoves 按行棋方视角从好到坏排列走法。
+type diceBoard struct {
+	scores   []float64
+	rounds   int
+	knockout float64
+	ordered  bool
+	path     []Move
+}
+
+func newDiceBoard(rounds int, scores ...float64) *diceBoard {
+	return &diceBoard{scores: scores, rounds: rounds, knockout: 8}
+}
+
+func (b *diceBoard) Print() { fmt.Println(b.path) }
+
+func (b *diceBoard) total() float64 {
+	total := 0.0
+	for _, m := range b.path {
+		switch m := m.(type) {
+		case pickMove:
+			total += b.scores[m]
+		case diceMove:
+			total += diceValues[m]
+		}
+	}
+	return total
+}
+
+func (b *diceBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() || b.IsChanceNode() {
+		return nil
+	}
+	moves := make([]Move, len(b.scores))
+	for i := range moves {
+		moves[i] = pickMove(i)
+	}
+	if b.ordered {
+		sort.SliceStable(moves, func(i, j int) bool {
+			si, sj := b.scores[moves[i].(pickMove)], b.scores[moves[j].(pickMove)]
+			return isMaxPlayer && si > sj || !isMaxPlayer && si < sj
+		})
+	}
+	return moves
+}
+
+func (b *diceBoard) Move(move Move) { b.path = append(b.path, move) }
+
+func (b *diceBoard) UndoMove(move Move) { b.path = b.path[:len(b.path)-1] }
+
+func (b *diceBoard) IsGameOver() bool {
+	if len(b.path) >= 2*b.rounds {
+		return true
+	}
+	total := b.total()
+	return !b.IsChanceNode() && len(b.path) > 0 && (total >= b.knockout || total <= -b.knockout)
+}
+
+func (b *diceBoard) IsChanceNode() bool { return len(b.path)%2 == 1 }
+
+func (b *diceBoard) ChanceOutcomes() []ChanceOutcome {
+	outcomes := make([]ChanceOutcome, len(diceValues))
+	for i := range outcomes {
+		outcomes[i] = ChanceOutcome{Move: diceMove(i), Probability: diceProbabilities[i]}
+	}
+	return outcomes
+}
+
+func (b *diceBoard) EvaluateFunc(opts EvalOptions) float64 { return b.total() }
+
+func (b *diceBoard) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, m := range b.path {
+		var v uint64
+		switch m := m.(type) {
+		case pickMove:
+			v = uint64(m) + 1
+		case diceMove:
+			v = uint64(m) + 101
+		}
+		h = (h ^ v) * 1099511628211
+	}
+	return h
+}
+
+func (b *diceBoard) Clone() Board {
+	clone := *b
+	clone.path = append([]Move(nil), b.path...)
+	return &clone
+}
+
+// resultDiceBoard 在 diceBoard 上实现 ResultBoard：终局时总分为正则最大化玩家获胜，为负则最小化玩家获胜。
+type resultDiceBoard struct {
+	*diceBoard
+}
+
+func (b resultDiceBoard) Result() GameResult {
+	total := b.total()
+	maxToMove := len(b.path)/2%2 == 0
+	switch {
+	case total == 0:
+		return Draw
+	case (total > 0) == maxToMove:
+		return Win
+	}
+	return Loss
+}
+
+func (b resultDiceBoard) Clone() Board {
+	return resultDiceBoard{b.diceBoard.Clone().(*diceBoard)}
+}
+
+// searchDice 用 tt 在 board 上以 [-20, 20] 的评估值界限搜索 depth 层并返回搜索结果。
+func searchDice(t *testing.T, tt GameTreeType, board Board, depth int, isMaxPlayer bool) *SearchResult {
+	t.Helper()
+	result, err := NewEvaluator(tt, NewEvaluatorOptions(
+		WithBoard(board),
+		WithDepth(depth),
+		WithIsMaxPlayer(isMaxPlayer),
+		WithEvalBounds(-20, 20),
+	)).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// assertSameAsExpectimax 检查 Star 的评估值与 Expectimax 相同，且其最佳走法是 Expectimax 的最佳走法之一。
+func assertSameAsExpectimax(t *testing.T, star, expectimax *SearchResult) {
+	t.Helper()
+	if star.Value != expectimax.Value {
+		t.Fatalf("value %v, want %v", star.Value, expectimax.Value)
+	}
+	if len(star.BestMoves) == 0 {
+		t.Fatalf("no best move, want one of %v", expectimax.BestMoves)
+	}
+	for _, m := range expectimax.BestMoves {
+		if m == star.BestMoves[0] {
+			return
+		}
+	}
+	t.Fatalf("best moves %v, want one of %v", star.BestMoves, expectimax.BestMoves)
+}
+
+func TestStarMatchesExpectimaxWithResultBoard(t *testing.T) {
+	for _, tt := range []GameTreeType{Star1, Star2} {
+		for depth := 1; depth <= 4; depth++ {
+			for _, isMaxPlayer := range []bool{true, false} {
+				t.Run(fmt.Sprintf("%d/depth=%d/max=%v", tt, depth, isMaxPlayer), func(t *testing.T) {
+					board := resultDiceBoard{newDiceBoard(3, -3, 1, 4, 7)}
+					want := searchDice(t, Expectimax, board, depth, isMaxPlayer)
+					got := searchDice(t, tt, board, depth, isMaxPlayer)
+					assertSameAsExpectimax(t, got, want)
+				})
+			}
+		}
+	}
+}
+
+// TestStarMatchesExpectimax 检查 Star1/Star2 与 Expectimax 的结果相同，并且在界限较紧时访问更少的节点。
+// Star1 的剪枝不会增加节点数；Star2 的试探在没有置换表时会被正式搜索重复，只在走法排序良好时才有收益。
+func TestStarMatchesExpectimax(t *testing.T) {
+	for _, tt := range []GameTreeType{Star1, Star2} {
+		for _, ordered := range []bool{false, true} {
+			for depth := 1; depth <= 4; depth++ {
+				for _, isMaxPlayer := range []bool{true, false} {
+					t.Run(fmt.Sprintf("%d/ordered=%v/depth=%d/max=%v", tt, ordered, depth, isMaxPlayer), func(t *testing.T) {
+						board := newDiceBoard(3, -3, 1, 4, 7)
+						board.ordered = ordered
+						want := searchDice(t, Expectimax, board, depth, isMaxPlayer)
+						got := searchDice(t, tt, board, depth, isMaxPlayer)
+						assertSameAsExpectimax(t, got, want)
+						if tt == Star1 && got.Stats.Nodes > want.Stats.Nodes {
+							t.Errorf("visited %d nodes, Expectimax visited %d", got.Stats.Nodes, want.Stats.Nodes)
+						}
+						if ordered && depth >= 3 && got.Stats.Nodes >= want.Stats.Nodes {
+							t.Errorf("visited %d nodes with good move ordering, want fewer than Expectimax's %d", got.Stats.Nodes, want.Stats.Nodes)
+						}
+					})
+				}
+			}
+		}
+	}
+}
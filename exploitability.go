@@ -0,0 +1,172 @@
+package gotack
+
+// Exploitability 返回当前平均策略组合的可利用度，即两名玩家各自最佳应对对方平均策略时收益之和的一半。
+// 可利用度为 0 时平均策略构成纳什均衡。计算需要遍历完整的博弈树，只适用于小型博弈。
+func (s *CFRSolver) Exploitability() float64 {
+	return (s.bestResponseValue(0) + s.bestResponseValue(1)) / 2
+}
+
+// bestResponseValue 返回玩家 player 针对对手平均策略的最佳应对收益。
+func (s *CFRSolver) bestResponseValue(player int) float64 {
+	br := &bestResponse{
+		solver:    s,
+		player:    player,
+		histories: make(map[string][]brHistory),
+		actions:   make(map[string]int),
+	}
+	br.collect(1)
+	return br.value()
+}
+
+// brHistory 记录最佳应对方信息集中的一个历史及其到达权重（对手与机会的到达概率之积）。
+type brHistory struct {
+	path   []Move
+	weight float64
+}
+
+// bestResponse 计算一名玩家针对对手平均策略的最佳应对。
+// 最佳应对方在每个信息集上选择使 Σ 权重·收益 最大的动作，由于完美回忆，信息集之间按深度递归求解即可。
+type bestResponse struct {
+	solver    *CFRSolver
+	player    int
+	path      []Move                 // 当前历史，即从根节点出发已应用的动作
+	histories map[string][]brHistory // 最佳应对方每个信息集包含的历史
+	actions   map[string]int         // 已求解的信息集最佳动作下标
+}
+
+// collect 遍历博弈树，记录最佳应对方每个信息集中的历史及其到达权重。
+func (br *bestResponse) collect(weight float64) {
+	g := br.solver.Game
+	if g.IsTerminal() {
+		return
+	}
+	if g.IsChance() {
+		for _, outcome := range g.ChanceOutcomes() {
+			br.move(outcome.Move)
+			br.collect(weight * outcome.Probability)
+			br.undo()
+		}
+		return
+	}
+
+	key := g.InfoSetKey()
+	actions := g.Actions()
+	if g.CurrentPlayer() == br.player {
+		br.histories[key] = append(br.histories[key], brHistory{path: append([]Move(nil), br.path...), weight: weight})
+		for _, action := range actions {
+			br.move(action)
+			br.collect(weight)
+			br.undo()
+		}
+		return
+	}
+	average := br.solver.averageOf(key, actions)
+	for i, action := range actions {
+		br.move(action)
+		br.collect(weight * average[i])
+		br.undo()
+	}
+}
+
+// value 返回当前历史下最佳应对方的期望收益。
+func (br *bestResponse) value() float64 {
+	g := br.solver.Game
+	if g.IsTerminal() {
+		return g.Utility(br.player)
+	}
+	if g.IsChance() {
+		value := 0.0
+		for _, outcome := range g.ChanceOutcomes() {
+			br.move(outcome.Move)
+			value += outcome.Probability * br.value()
+			br.undo()
+		}
+		return value
+	}
+
+	key := g.InfoSetKey()
+	actions := g.Actions()
+	if g.CurrentPlayer() == br.player {
+		action := actions[br.bestAction(key, len(actions))]
+		br.move(action)
+		value := br.value()
+		br.undo()
+		return value
+	}
+	average := br.solver.averageOf(key, actions)
+	value := 0.0
+	for i, action := range actions {
+		br.move(action)
+		value += average[i] * br.value()
+		br.undo()
+	}
+	return value
+}
+
+// bestAction 返回信息集上的最佳应对动作下标，结果会被缓存。
+// 求解时依次切换到信息集中的每个历史，求解结束后回到调用时的历史。
+func (br *bestResponse) bestAction(key string, numActions int) int {
+	if a, ok := br.actions[key]; ok {
+		return a
+	}
+	g := br.solver.Game
+	saved := append([]Move(nil), br.path...)
+	values := make([]float64, numActions)
+	for _, h := range br.histories[key] {
+		br.goTo(h.path)
+		for i, action := range g.Actions() {
+			br.move(action)
+			values[i] += h.weight * br.value()
+			br.undo()
+		}
+	}
+	br.goTo(saved)
+
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	br.actions[key] = best
+	return best
+}
+
+// move 应用一个动作并记录到当前历史。
+func (br *bestResponse) move(move Move) {
+	br.solver.Game.Move(move)
+	br.path = append(br.path, move)
+}
+
+// undo 撤销当前历史的最后一个动作。
+func (br *bestResponse) undo() {
+	last := br.path[len(br.path)-1]
+	br.solver.Game.UndoMove(last)
+	br.path = br.path[:len(br.path)-1]
+}
+
+// goTo 将博弈状态切换到 target 表示的历史：先撤销到公共前缀，再依次应用剩余动作。
+func (br *bestResponse) goTo(target []Move) {
+	common := 0
+	for common < len(br.path) && common < len(target) && br.path[common].String() == target[common].String() {
+		common++
+	}
+	for len(br.path) > common {
+		br.undo()
+	}
+	for _, move := range target[common:] {
+		br.move(move)
+	}
+}
+
+// averageOf 返回信息集的平均策略，未访问过的信息集使用均匀策略。
+func (s *CFRSolver) averageOf(key string, actions []Move) []float64 {
+	if node, ok := s.infoSets[key]; ok {
+		return node.averageStrategy()
+	}
+	average := make([]float64, len(actions))
+	for i := range average {
+		average[i] = 1 / float64(len(actions))
+	}
+	return average
+}
@@ -0,0 +1,164 @@
+package gotack
+
+import (
+	"math"
+	"sort"
+)
+
+// RootSelectionType 表示 MCTS 在根节点选择动作的方式。
+type RootSelectionType int
+
+const (
+	RootUCB    RootSelectionType = iota // 根节点与其他节点一样按 UCB 选择，最终选择访问次数最多的走法
+	RootGumbel                          // 根节点使用 Gumbel-Top-k 采样与 Sequential Halving，适合模拟次数很少的场景
+)
+
+// defaultGumbelIterations 是未设置 Iterations 时 Gumbel 根节点选择使用的模拟次数。
+const defaultGumbelIterations = 200
+
+// gumbelCandidate 记录根节点候选动作及其 Gumbel 噪声与先验 logit 之和。
+type gumbelCandidate struct {
+	node *Node
+	base float64
+}
+
+// gumbelSearch 使用 Gumbel-Top-k 采样与 Sequential Halving 在根节点分配模拟次数，并返回最终选中的子节点。
+//
+// 算法流程:
+//   - 为每个根走法采样 Gumbel 噪声 g(a)，与先验 logit(a) 相加后取前 GumbelK 个作为候选。
+//   - 将 Iterations 次模拟平均分配到 ⌈log2(k)⌉ 轮，每轮给每个候选相同的模拟次数，
+//     然后按 g(a)+logit(a)+σ(q̂(a)) 保留前一半候选，直到只剩一个。
+//   - σ(q̂) = (GumbelCVisit + 最大访问次数)·GumbelCScale·q̂，q̂ 是以根节点行棋方视角归一化到 [0, 1] 的平均奖励。
+//
+// 先验来自实现了 PriorBoard 接口的棋盘，否则视为均匀分布；噪声使用本次搜索的随机数生成器，可通过 Seed 复现。
+// 候选内部的子树仍然按 UCB 与渐进展开进行搜索。根局面为机会节点时以 ErrChanceRoot 中止搜索并返回 nil。
+func (e *Evaluator) gumbelSearch(root *Node, opts *EvalOptions) *Node {
+	if _, ok := chanceOutcomes(root.State); ok {
+		e.fail(ErrChanceRoot)
+		return nil
+	}
+	e.initMoves(root)
+	for root.ExpandedCount < len(root.UntriedMoves) {
+		e.addChild(root)
+	}
+	if len(root.Children) == 0 {
+		return nil
+	}
+
+	budget := opts.Iterations
+	if budget <= 0 {
+		budget = defaultGumbelIterations
+	}
+	if e.nodeLimit > 0 {
+		budget = min(budget, int(e.nodeLimit))
+	}
+	simulationThreshold := getOptionInt(opts.Extra, "SimThresh", 1)
+	aheadStep := opts.rolloutDepth()
+
+	logits := e.rootLogits(root)
+	candidates := make([]gumbelCandidate, len(root.Children))
+	for i, child := range root.Children {
+		candidates[i] = gumbelCandidate{node: child, base: e.sampleGumbel() + logits[i]}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].base > candidates[j].base
+	})
+	k := opts.GumbelK
+	if k <= 0 || k > len(candidates) {
+		k = len(candidates)
+	}
+	candidates = candidates[:k]
+
+	phases := int(math.Ceil(math.Log2(float64(k))))
+	for len(candidates) > 1 && !e.tm.softExpired() {
+		perAction := max(1, budget/(phases*len(candidates)))
+		for _, c := range candidates {
+			for j := 0; j < perAction && !e.nodesExhausted(); j++ {
+				e.nodes++
+				e.stats.Playouts++
+				node := e.selectNode(c.node, simulationThreshold)
+				e.reachDepth(node.ply())
+				e.playout(node, aheadStep)
+			}
+		}
+		e.sortGumbelCandidates(root, candidates)
+		candidates = candidates[:(len(candidates)+1)/2]
+	}
+
+	best := candidates[0].node
+	if best.Visits == 0 && !e.nodesExhausted() {
+		e.nodes++
+		e.stats.Playouts++
+		e.playout(e.selectNode(best, simulationThreshold), aheadStep)
+	}
+	return best
+}
+
+// sortGumbelCandidates 按 g(a)+logit(a)+σ(q̂(a)) 从大到小对候选排序。
+func (e *Evaluator) sortGumbelCandidates(root *Node, candidates []gumbelCandidate) {
+	qLow, qHigh := math.Inf(1), math.Inf(-1)
+	maxVisits := 0
+	for _, child := range root.Children {
+		if child.Visits == 0 {
+			continue
+		}
+		q := rootPerspectiveValue(root, child)
+		qLow = math.Min(qLow, q)
+		qHigh = math.Max(qHigh, q)
+		maxVisits = max(maxVisits, child.Visits)
+	}
+
+	scale := (e.EvalOptions.GumbelCVisit + float64(maxVisits)) * e.EvalOptions.GumbelCScale
+	score := func(c gumbelCandidate) float64 {
+		q := 0.5
+		if c.node.Visits > 0 && qHigh > qLow {
+			q = (rootPerspectiveValue(root, c.node) - qLow) / (qHigh - qLow)
+		}
+		return c.base + scale*q
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return score(candidates[i]) > score(candidates[j])
+	})
+}
+
+// rootLogits 返回根节点各子节点的先验 logit，棋盘未实现 PriorBoard 时全部为 0（均匀先验）。
+func (e *Evaluator) rootLogits(root *Node) []float64 {
+	logits := make([]float64, len(root.Children))
+	priorBoard, ok := root.State.(PriorBoard)
+	if !ok {
+		return logits
+	}
+	moves := make([]Move, len(root.Children))
+	for i, child := range root.Children {
+		moves[i] = child.Move
+	}
+	priors := priorBoard.MovePriors(moves, root.IsMaxPlayer)
+	for i := range logits {
+		if i < len(priors) {
+			logits[i] = math.Log(math.Max(priors[i], 1e-12))
+		}
+	}
+	return logits
+}
+
+// sampleGumbel 从标准 Gumbel(0, 1) 分布中采样。
+func (e *Evaluator) sampleGumbel() float64 {
+	u := e.rng.Float64()
+	for u == 0 {
+		u = e.rng.Float64()
+	}
+	return -math.Log(-math.Log(u))
+}
+
+// rootPerspectiveValue 返回子节点以根节点行棋方视角衡量的平均奖励（越大越好）。
+// 多人棋盘直接取根节点行棋方的累计奖励；两人棋盘的奖励以最大化玩家为视角，根节点为最小化玩家时取相反数。
+func rootPerspectiveValue(root, child *Node) float64 {
+	if child.TotalRewards != nil {
+		return child.TotalRewards[root.Player] / float64(child.Visits)
+	}
+	q := child.TotalReward / float64(child.Visits)
+	if !root.IsMaxPlayer {
+		q = -q
+	}
+	return q
+}
@@ -0,0 +1,100 @@
+package gotack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// priorPickBoard 在 pickBoard 上实现 PriorBoard，先验集中在 favourite 上。
+type priorPickBoard struct {
+	*pickBoard
+	favourite pickMove
+}
+
+func (b priorPickBoard) MovePriors(moves []Move, isMaxPlayer bool) []float64 {
+	priors := make([]float64, len(moves))
+	for i, m := range moves {
+		priors[i] = 0.001
+		if m == b.favourite {
+			priors[i] = 1
+		}
+	}
+	return priors
+}
+
+func (b priorPickBoard) Clone() Board {
+	return priorPickBoard{b.pickBoard.Clone().(*pickBoard), b.favourite}
+}
+
+// searchGumbel 用 Gumbel 根节点选择搜索 board 并返回搜索结果。
+func searchGumbel(t *testing.T, board Board, opts ...EvalOption) *SearchResult {
+	t.Helper()
+	result, err := NewEvaluator(UCT, NewEvaluatorOptions(append([]EvalOption{
+		WithBoard(board),
+		WithRootSelection(RootGumbel),
+		WithTimeLimit(0),
+	}, opts...)...)).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestGumbelFindsBestMove(t *testing.T) {
+	scores := []float64{3, 8, 1, 5, 9, 0, 6, 2}
+	for _, iterations := range []int{50, 100, 200} {
+		for _, isMaxPlayer := range []bool{true, false} {
+			for seed := int64(1); seed <= 5; seed++ {
+				t.Run(fmt.Sprintf("%d/max=%v/seed=%d", iterations, isMaxPlayer, seed), func(t *testing.T) {
+					// 8 个候选经过 3 轮 Sequential Halving，每轮每个候选至少模拟一次，
+					// 最大化玩家应选出分数最高的 4，最小化玩家应选出分数最低的 5。
+					want := pickMove(4)
+					if !isMaxPlayer {
+						want = pickMove(5)
+					}
+					result := searchGumbel(t, newPickBoard(1, scores...),
+						WithIterations(iterations), WithIsMaxPlayer(isMaxPlayer), WithSeed(seed), WithGumbel(8, 50, 1))
+					if len(result.BestMoves) == 0 || result.BestMoves[0] != want {
+						t.Fatalf("best moves %v, want %v", result.BestMoves, want)
+					}
+					if result.Stats.Playouts > int64(iterations) {
+						t.Fatalf("%d playouts, want at most %d", result.Stats.Playouts, iterations)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestGumbelUsesPriors(t *testing.T) {
+	scores := []float64{3, 8, 1, 5, 9, 0, 6, 2}
+	for seed := int64(1); seed <= 5; seed++ {
+		// 只保留一个候选时不做 Sequential Halving，选中的就是先验集中的走法。
+		board := priorPickBoard{newPickBoard(1, scores...), 2}
+		result := searchGumbel(t, board, WithIterations(50), WithSeed(seed), WithGumbel(1, 50, 1))
+		if len(result.BestMoves) == 0 || result.BestMoves[0] != pickMove(2) {
+			t.Fatalf("seed %d: best moves %v with k=1, want the favoured move 2", seed, result.BestMoves)
+		}
+
+		// 有足够的候选与模拟次数时，价值项会推翻错误的先验。
+		result = searchGumbel(t, board, WithIterations(200), WithSeed(seed), WithGumbel(8, 50, 1))
+		if len(result.BestMoves) == 0 || result.BestMoves[0] != pickMove(4) {
+			t.Fatalf("seed %d: best moves %v with k=8, want the best move 4", seed, result.BestMoves)
+		}
+	}
+}
+
+func TestGumbelRejectsChanceRoot(t *testing.T) {
+	board := newDiceBoard(2, -3, 1, 4)
+	board.Move(pickMove(0))
+	_, err := NewEvaluator(UCT, NewEvaluatorOptions(
+		WithBoard(board),
+		WithRootSelection(RootGumbel),
+		WithIterations(50),
+		WithTimeLimit(0),
+	)).Search()
+	if !errors.Is(err, ErrChanceRoot) {
+		t.Fatalf("error %v, want ErrChanceRoot", err)
+	}
+}
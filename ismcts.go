@@ -0,0 +1,117 @@
+package gotack
+
+import (
+	"math"
+	"time"
+)
+
+// ismcts 实现单观察者信息集蒙特卡洛树搜索（SO-ISMCTS）。
+// 树中的节点表示根节点行棋方视角下的信息集，子节点以 Move.String() 区分。
+// 每次迭代先通过 Determinizer 采样一个确定化棋盘，然后只在该棋盘下合法的子节点中按UCB选择，
+// 出现该棋盘下尚未扩展的走法时随机扩展一个，再从确定化棋盘模拟并反向传播。
+// 子节点的 Availability 记录它在多少次迭代中是合法的，UCB 的探索项以它代替父节点访问次数。
+func (e *Evaluator) ismcts(opts *EvalOptions) (float64, []Move) {
+	determinizer, ok := e.Board.(Determinizer)
+	if !ok {
+		return 0.0, nil
+	}
+
+	iterations := opts.Iterations
+	if iterations == 0 {
+		iterations = math.MaxInt32
+	}
+	aheadStep := opts.rolloutDepth()
+
+	root := &Node{IsMaxPlayer: opts.IsMaxPlayer}
+	e.stats.TreeSize++
+	var lastInfo time.Duration
+	for i := 0; i < iterations; i++ {
+		if i > 0 && (e.tm.softExpired() || e.nodesExhausted()) {
+			break
+		}
+		if i > 0 && i%timeCheckInterval == 0 {
+			e.reportTree(root, &lastInfo)
+		}
+		e.nodes++
+		e.stats.Playouts++
+
+		state := determinizer.Determinize(opts.IsMaxPlayer, e.rng)
+		node := e.selectInformationSet(root, state)
+		e.reachDepth(node.ply())
+		leaf := &Node{State: state, IsMaxPlayer: node.IsMaxPlayer}
+		e.backpropagate(node, e.simulate(leaf, aheadStep))
+	}
+
+	return e.selectBestMove(root)
+}
+
+// selectInformationSet 在确定化棋盘 state 上从 node 向下选择，直到扩展出新节点或到达终局，
+// 并在 state 上应用沿途的走法。返回本次迭代的叶节点。
+func (e *Evaluator) selectInformationSet(node *Node, state Board) *Node {
+	for !state.IsGameOver() {
+		moves := e.legalMoves(state, node.IsMaxPlayer)
+		if len(moves) == 0 {
+			break
+		}
+
+		var untried []Move
+		var legalChildren []*Node
+		for _, move := range moves {
+			if child := findChild(node, move); child != nil {
+				legalChildren = append(legalChildren, child)
+			} else {
+				untried = append(untried, move)
+			}
+		}
+
+		if len(untried) > 0 {
+			move := untried[e.rng.Intn(len(untried))]
+			child := &Node{Parent: node, IsMaxPlayer: !node.IsMaxPlayer, Move: move}
+			node.Children = append(node.Children, child)
+			e.stats.TreeSize++
+			for _, c := range legalChildren {
+				c.Availability++
+			}
+			child.Availability++
+			state.Move(move)
+			return child
+		}
+
+		best := legalChildren[0]
+		bestValue := math.Inf(-1)
+		for _, child := range legalChildren {
+			child.Availability++
+			if value := child.isUCTValue(node.IsMaxPlayer); value > bestValue {
+				bestValue = value
+				best = child
+			}
+		}
+		state.Move(best.Move)
+		node = best
+	}
+	return node
+}
+
+// isUCTValue 返回 ISMCTS 中子节点以父节点行棋方视角衡量的UCB值，探索项使用可用次数 Availability。
+func (n *Node) isUCTValue(parentIsMaxPlayer bool) float64 {
+	if n.Visits == 0 {
+		return math.Inf(1)
+	}
+	avgReward := n.TotalReward / float64(n.Visits)
+	if !parentIsMaxPlayer {
+		avgReward = -avgReward
+	}
+	exploration := math.Sqrt(2 * math.Log(float64(n.Availability)) / float64(n.Visits))
+	return avgReward + exploration
+}
+
+// findChild 返回走法与 move 相同（按 Move.String() 比较）的子节点，不存在时返回 nil。
+func findChild(node *Node, move Move) *Node {
+	key := move.String()
+	for _, child := range node.Children {
+		if child.Move.String() == key {
+			return child
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package gotack
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// betMove 是 cardBoard 上的下注走法。
+type betMove string
+
+func (m betMove) String() string { return string(m) }
+
+// cardDeck 是隐藏牌的公开分布：牌 0 的概率为 1/2，牌 1 与牌 2 各为 1/4。
+var cardDeck = []int{0, 0, 1, 2}
+
+// cardPayoffs 是每种下注在每张隐藏牌下对最大化玩家的收益。
+// 期望收益分别为 a=0.5、b=1.5、c=1，而已知隐藏牌为 0 时 a 最好。
+var cardPayoffs = map[betMove][]float64{
+	"a": {4, -3, -3},
+	"b": {0, 3, 3},
+	"c": {1, 1, 1},
+}
+
+// cardBoard 是一个只有一步的隐藏信息游戏：最大化玩家看不到隐藏牌，选择一种下注后游戏结束并按牌结算。
+// 真实棋盘与它的克隆共享 peeks 计数器，每读取一次隐藏牌就加一；Determinize 采样的棋盘没有计数器，
+// 并把每次采样到的牌记录在 samples 中。
+type cardBoard struct {
+	card    int
+	bet     betMove
+	peeks   *int
+	samples *[]int
+}
+
+func (b *cardBoard) hidden() int {
+	if b.peeks != nil {
+		*b.peeks++
+	}
+	return b.card
+}
+
+func (b *cardBoard) Print() { fmt.Println(b.hidden(), b.bet) }
+
+func (b *cardBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() {
+		return nil
+	}
+	return []Move{betMove("a"), betMove("b"), betMove("c")}
+}
+
+func (b *cardBoard) Move(move Move) { b.bet = move.(betMove) }
+
+func (b *cardBoard) UndoMove(move Move) { b.bet = "" }
+
+func (b *cardBoard) IsGameOver() bool { return b.bet != "" }
+
+func (b *cardBoard) EvaluateFunc(opts EvalOptions) float64 {
+	if b.bet == "" {
+		return 0
+	}
+	return cardPayoffs[b.bet][b.hidden()]
+}
+
+func (b *cardBoard) Hash() uint64 { return uint64(len(b.bet)) }
+
+func (b *cardBoard) Clone() Board {
+	clone := *b
+	return &clone
+}
+
+func (b *cardBoard) Determinize(isMaxPlayer bool, rng *rand.Rand) Board {
+	card := cardDeck[rng.Intn(len(cardDeck))]
+	*b.samples = append(*b.samples, card)
+	return &cardBoard{card: card, bet: b.bet}
+}
+
+func TestISMCTSBestMoveInExpectation(t *testing.T) {
+	peeks := 0
+	var samples []int
+	board := &cardBoard{card: 0, peeks: &peeks, samples: &samples}
+	e := NewEvaluator(ISMCTS, NewEvaluatorOptions(
+		WithBoard(board),
+		WithIterations(3000),
+		WithTimeLimit(0),
+		WithSeed(7),
+	))
+	result, err := e.Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peeks != 0 {
+		t.Fatalf("search read the true hidden card %d times", peeks)
+	}
+	if len(samples) != 3000 {
+		t.Fatalf("%d determinizations sampled, want one per iteration", len(samples))
+	}
+
+	// 以搜索实际采样到的确定化棋盘计算每种下注的期望收益。
+	var best betMove
+	bestValue := 0.0
+	for _, move := range []betMove{"a", "b", "c"} {
+		value := 0.0
+		for _, card := range samples {
+			value += cardPayoffs[move][card]
+		}
+		value /= float64(len(samples))
+		if best == "" || value > bestValue {
+			best, bestValue = move, value
+		}
+	}
+	if best != "b" {
+		t.Fatalf("sampled expectation favours %v, the fixed seed no longer reproduces the intended distribution", best)
+	}
+	if len(result.BestMoves) != 1 || result.BestMoves[0] != best {
+		t.Fatalf("best moves %v, want [%v]", result.BestMoves, best)
+	}
+}
+
+func TestISMCTSReproducibleWithSeed(t *testing.T) {
+	run := func() []Move {
+		var samples []int
+		board := &cardBoard{peeks: new(int), samples: &samples}
+		e := NewEvaluator(ISMCTS, NewEvaluatorOptions(WithBoard(board), WithIterations(200), WithTimeLimit(0), WithSeed(11)))
+		result, err := e.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.BestMoves
+	}
+	first, second := run(), run()
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatalf("same seed gave %v and %v", first, second)
+	}
+}
@@ -0,0 +1,151 @@
+package gotack
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrChanceRoot 表示根局面是机会节点，没有可供评估或选择的玩家走法。
+var ErrChanceRoot = errors.New("gotack: root position is a chance node")
+
+// MoveScore 表示根节点一个走法的评估结果。
+type MoveScore struct {
+	// Move 为根节点的走法。
+	Move Move
+	// Value 为走出该走法后局面以最大化玩家视角的评估值，多人棋盘为根节点行棋方的分数。
+	Value float64
+	// Score 为根节点行棋方视角的评估值，越大越好。
+	Score float64
+	// Loss 为该走法比最佳走法少得的分数，最佳走法为 0。
+	Loss float64
+	// MateIn 为走出该走法后根节点行棋方已知的胜负距离，含义与 SearchResult.MateIn 相同。
+	MateIn int
+	// PV 为以该走法开头的主要变例。
+	PV []Move
+}
+
+// AnalyzeMoves 逐个评估根局面 GetAllMoves 给出的全部走法，返回按 Score 从好到坏排列的列表，Score 相同时保持走法生成的顺序。
+// 每个走法之后的局面都用 TreeType 指定的算法单独搜索：依赖深度的算法搜索 Depth-1 层，
+// 时间由 MoveTime、Clock 或 TimeLimit 得到的本步用时与 MaxNodes 都在各走法之间平分（AlphaBeta/PVS 与 Search 一样只受 MoveTime 与 Clock 限制），
+// MCTS 类算法每个走法使用 Iterations 次模拟。开局库、MultiPV、MoveSelection 与 Skill 在评估中不起作用，e.Board 与 e.Result 不会被修改。
+//
+// 返回值:
+//   - []MoveScore: 全部走法的评估结果，根局面已结束或没有合法走法时为空。
+//   - error: 根局面为机会节点时返回 ErrChanceRoot，SimultaneousUCT 返回 ErrUnsupportedTreeType，其余为各走法搜索返回的错误。
+func (e *Evaluator) AnalyzeMoves() ([]MoveScore, error) {
+	if e.TreeType == SimultaneousUCT {
+		return nil, ErrUnsupportedTreeType
+	}
+	if _, ok := chanceOutcomes(e.Board); ok {
+		return nil, ErrChanceRoot
+	}
+	e.initPlayers()
+	isMaxPlayer := e.EvalOptions.IsMaxPlayer
+	if e.multiPlayer {
+		isMaxPlayer = true
+	}
+	if e.Board.IsGameOver() {
+		return nil, nil
+	}
+	moves := e.legalMoves(e.Board, isMaxPlayer)
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	opts := e.EvalOptions
+	var moveTime time.Duration
+	if tm := newTimeManager(opts); tm.soft < forever && (tm.managed || (e.TreeType != AlphaBeta && e.TreeType != PVS)) {
+		moveTime = max(tm.soft/time.Duration(len(moves)), time.Millisecond)
+	}
+	rng := opts.newRand()
+	history := append(append([]uint64(nil), opts.History...), e.Board.Hash())
+
+	scores := make([]MoveScore, 0, len(moves))
+	for _, move := range moves {
+		board := e.Board.Clone()
+		board.Move(move)
+
+		childOpts := cloneOptions(e.EvalOptions, board)
+		childOpts.Depth = max(opts.Depth-1, 0)
+		childOpts.IsMaxPlayer = !isMaxPlayer
+		childOpts.History = history
+		childOpts.Clock = nil
+		childOpts.TimeLimit = 0
+		childOpts.MoveTime = moveTime
+		if budget := opts.nodeBudget(); budget > 0 {
+			childOpts.MaxNodes = max(budget/int64(len(moves)), 1)
+		}
+		childOpts.Seed = rng.Int63()
+		childOpts.RandSource = nil
+		childOpts.OpeningBook = nil
+		childOpts.MultiPV = 0
+		childOpts.Skill = nil
+		childOpts.MoveSelection = SelectTies
+		child := NewEvaluator(e.TreeType, childOpts)
+		child.rootPlayer = e.rootPlayer
+		child.fixedRootPlayer = e.multiPlayer
+
+		pv := []Move{move}
+		var value float64
+		if board.IsGameOver() || (childOpts.Depth == 0 && e.TreeType.usesDepth()) {
+			value = child.staticValue()
+		} else {
+			result, err := child.Search()
+			if err != nil {
+				return nil, err
+			}
+			value = result.Value
+			pv = append(pv, result.PV...)
+		}
+		value = addPly(value)
+
+		score := value
+		if !e.multiPlayer {
+			score = e.rootScore(value)
+		}
+		scores = append(scores, MoveScore{
+			Move:   move,
+			Value:  value,
+			Score:  score,
+			MateIn: mateIn(value, e.multiPlayer || e.EvalOptions.IsMaxPlayer),
+			PV:     pv,
+		})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	for i := range scores {
+		scores[i].Loss = scores[0].Score - scores[i].Score
+	}
+	return scores, nil
+}
+
+// usesDepth 检查算法是否按 Depth 限制搜索深度。
+func (t GameTreeType) usesDepth() bool {
+	switch t {
+	case AlphaBeta, PVS, Expectimax, Star1, Star2, MaxN, Paranoid, BRS:
+		return true
+	}
+	return false
+}
+
+// staticValue 返回 e.Board 不经搜索的评估值：多人棋盘为根节点行棋方的分数，其余棋盘与搜索中的叶节点相同。
+func (e *Evaluator) staticValue() float64 {
+	if mp, ok := e.Board.(MultiPlayerBoard); ok {
+		return mp.PlayerScores(*e.EvalOptions)[e.rootPlayer]
+	}
+	return e.evaluateLeaf(e.EvalOptions.IsMaxPlayer, 0, e.EvalOptions)
+}
+
+// addPly 将子局面的胜负评估值转换为父局面的视角，胜负距离增加一步，其余评估值不变。
+func addPly(value float64) float64 {
+	switch {
+	case mateIn(value, true) > 0:
+		return value - 1
+	case mateIn(value, true) < 0:
+		return value + 1
+	}
+	return value
+}
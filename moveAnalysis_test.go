@@ -0,0 +1,98 @@
+package gotack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeMovesMatchesRootSearch(t *testing.T) {
+	// 走出 i 之后双方各自回应对自己最好的 1 分与 5 分，因此走法 i 的值为 scores[i]+6。
+	scores := []float64{1, 5, 3, 4}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS, Expectimax} {
+		for _, isMaxPlayer := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%d/max=%v", tt, isMaxPlayer), func(t *testing.T) {
+				board := newPickBoard(3, scores...)
+				opts := []EvalOption{WithBoard(board), WithDepth(3), WithIsMaxPlayer(isMaxPlayer)}
+				e := NewEvaluator(tt, NewEvaluatorOptions(opts...))
+				moves, err := e.AnalyzeMoves()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(board.path) != 0 || e.Result != nil {
+					t.Fatalf("AnalyzeMoves modified the board %v or the result", board.path)
+				}
+				if len(moves) != len(scores) {
+					t.Fatalf("%d moves, want %d", len(moves), len(scores))
+				}
+				for i, ms := range moves {
+					if want := scores[ms.Move.(pickMove)] + 6; ms.Value != want {
+						t.Errorf("%v: value %v, want %v", ms.Move, ms.Value, want)
+					}
+					if ms.Score != e.rootScore(ms.Value) || ms.Loss != moves[0].Score-ms.Score {
+						t.Errorf("%v: score %v loss %v for value %v", ms.Move, ms.Score, ms.Loss, ms.Value)
+					}
+					if i > 0 && ms.Score > moves[i-1].Score {
+						t.Errorf("%v ranked after a worse move", ms.Move)
+					}
+				}
+
+				result, err := e.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if moves[0].Move != result.BestMoves[0] || moves[0].Value != result.Value || moves[0].Loss != 0 {
+					t.Fatalf("best analyzed move %+v, root search gives %v with value %v", moves[0], result.BestMoves, result.Value)
+				}
+				if tt == Expectimax {
+					return
+				}
+
+				// AlphaBeta/PVS 的 Multi-PV 搜索给出全部根节点走法各自的值与主要变例。
+				result, err = NewEvaluator(tt, NewEvaluatorOptions(append(opts, WithMultiPV(len(scores)))...)).Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.Lines) != len(moves) {
+					t.Fatalf("%d Multi-PV lines, want %d", len(result.Lines), len(moves))
+				}
+				for i, line := range result.Lines {
+					if ms := moves[i]; ms.Move != line.Move || ms.Value != line.Value || fmt.Sprint(ms.PV) != fmt.Sprint(line.PV) {
+						t.Errorf("move %d is %v with value %v PV %v, Multi-PV gives %v with value %v PV %v",
+							i, ms.Move, ms.Value, ms.PV, line.Move, line.Value, line.PV)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAnalyzeMovesMultiPlayer(t *testing.T) {
+	moves, err := NewEvaluator(MaxN, NewEvaluatorOptions(WithBoard(&threeBoard{}), WithDepth(2))).AnalyzeMoves()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ms := range moves {
+		got = append(got, fmt.Sprintf("%v:%v:%v", ms.Move, ms.Score, ms.Loss))
+	}
+	if fmt.Sprint(got) != "[risky:5:0 safe:2:3]" {
+		t.Fatalf("moves %v, want risky then safe with scores for player 0", got)
+	}
+}
+
+func TestAnalyzeMovesErrors(t *testing.T) {
+	chance := newDiceBoard(2, -3, 1, 4)
+	chance.Move(pickMove(0))
+	if _, err := NewEvaluator(Expectimax, NewEvaluatorOptions(WithBoard(chance))).AnalyzeMoves(); !errors.Is(err, ErrChanceRoot) {
+		t.Fatalf("error %v at a chance root, want ErrChanceRoot", err)
+	}
+	if _, err := NewEvaluator(SimultaneousUCT, NewEvaluatorOptions(WithBoard(&penniesBoard{}))).AnalyzeMoves(); !errors.Is(err, ErrUnsupportedTreeType) {
+		t.Fatalf("error %v with SimultaneousUCT, want ErrUnsupportedTreeType", err)
+	}
+	over := newPickBoard(1, 1, 2)
+	over.Move(pickMove(0))
+	if moves, err := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(over))).AnalyzeMoves(); err != nil || len(moves) != 0 {
+		t.Fatalf("moves %v error %v after the game is over, want none", moves, err)
+	}
+}
@@ -0,0 +1,65 @@
+package gotack
+
+// MoveSelectionType 表示搜索结束后从根节点走法中选出最终走法的方式。
+type MoveSelectionType int
+
+const (
+	SelectTies      MoveSelectionType = iota // 保留评估值相同的全部最佳走法，由调用者自行选择
+	SelectFirst                              // 选择第一个最佳走法
+	SelectRandomTie                          // 在评估值相同的最佳走法中随机选择，UCT 为访问次数相同的子节点
+	SelectMargin                             // 在评估值与最佳走法相差不超过 SelectionMargin 的走法中选择
+	SelectSoftmax                            // 按 softmax(评估值 / SelectionTemperature) 的分布选择
+)
+
+// needsRootScores 检查该选择方式在 AlphaBeta/PVS 中是否需要搜索全部根节点走法的精确评估值。
+// Alpha-Beta 剪枝得到的并列走法中可能混有只证明了上界的走法，因此随机选择并列走法时也需要精确的评估值。
+func (s MoveSelectionType) needsRootScores() bool {
+	return s == SelectRandomTie || s == SelectMargin || s == SelectSoftmax
+}
+
+// selectMove 按 MoveSelection 从搜索结果中选出最终走法，返回其评估值与只含该走法的最佳走法，并更新主要变例。
+// 有各走法评估值时（AlphaBeta/PVS 的 Multi-PV 结果或 MCTS 的根节点子节点）在其中选择，否则在并列的最佳走法中选择。
+func (e *Evaluator) selectMove(value float64, bestMoves []Move) (float64, []Move) {
+	selection := e.EvalOptions.MoveSelection
+	if selection == SelectTies || len(bestMoves) == 0 {
+		return value, bestMoves
+	}
+	choices := e.rootChoices()
+	if selection == SelectFirst || len(choices) == 0 {
+		move := bestMoves[0]
+		if selection != SelectFirst {
+			move = bestMoves[e.rng.Intn(len(bestMoves))]
+		}
+		if len(e.pv) == 0 || e.pv[0].String() != move.String() {
+			e.pv = []Move{move}
+		}
+		e.BestMoves = []Move{move}
+		return value, e.BestMoves
+	}
+
+	var index int
+	scores := make([]float64, len(choices))
+	for i, choice := range choices {
+		scores[i] = choice.score
+	}
+	switch selection {
+	case SelectRandomTie:
+		if e.tree != nil {
+			visits := make([]float64, len(choices))
+			for i, choice := range choices {
+				visits[i] = float64(choice.visits)
+			}
+			index = sampleMargin(visits, 0, 0, e.rng)
+		} else {
+			index = sampleMargin(scores, 0, 0, e.rng)
+		}
+	case SelectMargin:
+		index = sampleMargin(scores, e.EvalOptions.SelectionMargin, e.EvalOptions.SelectionTemperature, e.rng)
+	case SelectSoftmax:
+		index = sampleSoftmax(scores, e.EvalOptions.SelectionTemperature, e.rng)
+	}
+	choice := choices[index]
+	e.pv = choice.pv
+	e.BestMoves = []Move{choice.move}
+	return choice.value, e.BestMoves
+}
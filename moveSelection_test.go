@@ -0,0 +1,38 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSelectMarginAgreesUnderUCTAndAlphaBeta(t *testing.T) {
+	// 根节点为最小化玩家，与最佳走法 0 相差不超过 6 的只有走法 0 与 1。
+	chosen := make(map[GameTreeType]map[Move]bool)
+	for _, tt := range []GameTreeType{AlphaBeta, UCT} {
+		chosen[tt] = make(map[Move]bool)
+		for seed := int64(1); seed <= 20; seed++ {
+			result, err := NewEvaluator(tt, NewEvaluatorOptions(
+				WithBoard(newPickBoard(1, 0, 5, 10)),
+				WithDepth(1),
+				WithIterations(300),
+				WithTimeLimit(0),
+				WithIsMaxPlayer(false),
+				WithSeed(seed),
+				WithMoveSelection(SelectMargin, 6, 0),
+			)).Search()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.BestMoves) != 1 {
+				t.Fatalf("%d: best moves %v, want a single move", tt, result.BestMoves)
+			}
+			chosen[tt][result.BestMoves[0]] = true
+		}
+	}
+	want := map[Move]bool{pickMove(0): true, pickMove(1): true}
+	for tt, moves := range chosen {
+		if fmt.Sprint(moves) != fmt.Sprint(want) {
+			t.Errorf("%d: chose %v, want %v", tt, moves, want)
+		}
+	}
+}
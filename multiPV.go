@@ -0,0 +1,92 @@
+package gotack
+
+import "sort"
+
+// PVLine 表示 Multi-PV 搜索中根节点一个候选走法的评估结果。
+type PVLine struct {
+	// Move 为根节点的候选走法。
+	Move Move
+	// Value 为该走法以最大化玩家视角的评估值，UCT 为该子节点的平均奖励。
+	Value float64
+	// MateIn 为走出该走法后根节点行棋方已知的胜负距离，含义与 SearchResult.MateIn 相同。
+	MateIn int
+	// PV 为以该走法开头的主要变例。
+	PV []Move
+	// Visits 为 UCT 中该子节点的访问次数，其余算法为 0。
+	Visits int
+}
+
+// multiPVSearch 对 AlphaBeta/PVS 的根节点进行 Multi-PV 搜索：依次以完整窗口搜索 MultiPV 次，
+// 每次在根节点排除之前已经找到的走法，从而得到最好的若干个走法各自的评估值与主要变例。
+// SkillLevel 或 MoveSelection 需要按评估值选择走法时对全部根节点走法进行搜索。
+// 返回第一次搜索的结果，其主要变例会恢复到 pvTable[0]；搜索中止时不更新 e.lines。
+func (e *Evaluator) multiPVSearch(search func(depth int) (float64, []Move), depth int) (float64, []Move) {
+	count := min(e.multiPV, len(e.legalMoves(e.Board, e.EvalOptions.IsMaxPlayer)))
+	defer func() { e.excluded = nil }()
+
+	var value float64
+	var bestMoves []Move
+	var lines []PVLine
+	e.excluded = make(map[string]bool, count)
+	for len(lines) < count {
+		v, moves := search(depth)
+		if e.stopped || len(moves) == 0 || len(e.pvTable[0]) == 0 {
+			break
+		}
+		if lines == nil {
+			value, bestMoves = v, moves
+		}
+		pv := append([]Move(nil), e.pvTable[0]...)
+		lines = append(lines, PVLine{Move: pv[0], Value: v, MateIn: mateIn(v, e.EvalOptions.IsMaxPlayer), PV: pv})
+		e.excluded[pv[0].String()] = true
+	}
+	if e.stopped || lines == nil {
+		return value, bestMoves
+	}
+	e.pvTable[0] = append(e.pvTable[0][:0], lines[0].PV...)
+	e.BestMoves = bestMoves
+	e.lines = lines
+	return value, bestMoves
+}
+
+// rootMoves 从根节点的走法中去掉 Multi-PV 搜索已经找到的走法。
+func (e *Evaluator) rootMoves(moves []Move) []Move {
+	if len(e.excluded) == 0 {
+		return moves
+	}
+	remaining := make([]Move, 0, len(moves))
+	for _, move := range moves {
+		if !e.excluded[move.String()] {
+			remaining = append(remaining, move)
+		}
+	}
+	return remaining
+}
+
+// treeLines 返回 MCTS 根节点访问次数最多的 MultiPV 个子节点，按访问次数从多到少排列。
+func (e *Evaluator) treeLines(root *Node) []PVLine {
+	if root == nil {
+		return nil
+	}
+	var lines []PVLine
+	for _, child := range root.Children {
+		if child.Visits == 0 || child.Move == nil {
+			continue
+		}
+		value := child.TotalReward / float64(child.Visits)
+		lines = append(lines, PVLine{
+			Move:   child.Move,
+			Value:  value,
+			MateIn: mateIn(value, e.EvalOptions.IsMaxPlayer),
+			PV:     principalVariation(child),
+			Visits: child.Visits,
+		})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Visits > lines[j].Visits
+	})
+	if len(lines) > e.EvalOptions.MultiPV {
+		lines = lines[:e.EvalOptions.MultiPV]
+	}
+	return lines
+}
@@ -0,0 +1,80 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMultiPVOrdersRootMoves(t *testing.T) {
+	// 对手总是回应对自己最好的走法：最小化玩家回应 0，最大化玩家回应 1，根节点走法 i 的值为 scores[i] 加上回应的分数。
+	scores := []float64{1, 5, 3, 4}
+	tests := []struct {
+		isMaxPlayer bool
+		multiPV     int
+		reply       pickMove
+		want        string
+	}{
+		{true, 3, 0, "[1:6 3:5 2:4]"},
+		{true, 10, 0, "[1:6 3:5 2:4 0:2]"},
+		{false, 3, 1, "[0:6 2:8 3:9]"},
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS} {
+		for _, tc := range tests {
+			t.Run(fmt.Sprintf("%d/max=%v/multiPV=%d", tt, tc.isMaxPlayer, tc.multiPV), func(t *testing.T) {
+				result, err := NewEvaluator(tt, NewEvaluatorOptions(
+					WithBoard(newPickBoard(2, scores...)),
+					WithDepth(2),
+					WithIsMaxPlayer(tc.isMaxPlayer),
+					WithMultiPV(tc.multiPV),
+				)).Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got []string
+				for _, line := range result.Lines {
+					got = append(got, fmt.Sprintf("%v:%v", line.Move, line.Value))
+					if len(line.PV) != 2 || line.PV[0] != line.Move || line.PV[1] != tc.reply {
+						t.Errorf("PV %v for %v, want the move followed by the reply %v", line.PV, line.Move, tc.reply)
+					}
+				}
+				if fmt.Sprint(got) != tc.want {
+					t.Fatalf("lines %v, want %s", got, tc.want)
+				}
+				first := result.Lines[0]
+				if result.BestMoves[0] != first.Move || result.Value != first.Value || fmt.Sprint(result.PV) != fmt.Sprint(first.PV) {
+					t.Fatalf("best moves %v value %v PV %v do not match the first line %+v", result.BestMoves, result.Value, result.PV, first)
+				}
+			})
+		}
+	}
+}
+
+func TestMultiPVUCT(t *testing.T) {
+	scores := []float64{1, 5, 3, 4}
+	result, err := NewEvaluator(UCT, NewEvaluatorOptions(
+		WithBoard(newPickBoard(1, scores...)),
+		WithIterations(3000),
+		WithTimeLimit(0),
+		WithSeed(1),
+		WithMultiPV(3),
+	)).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Lines) != 3 {
+		t.Fatalf("%d lines, want 3", len(result.Lines))
+	}
+	// 访问次数最多的子节点排在前面，每一行的值为该子节点的平均奖励。
+	want := []pickMove{1, 3, 2}
+	for i, line := range result.Lines {
+		if line.Move != want[i] || line.Value != scores[want[i]] {
+			t.Errorf("line %d is %v with value %v, want %v with value %v", i, line.Move, line.Value, want[i], scores[want[i]])
+		}
+		if i > 0 && line.Visits > result.Lines[i-1].Visits {
+			t.Errorf("line %d has %d visits, more than the line before it", i, line.Visits)
+		}
+	}
+	if result.BestMoves[0] != result.Lines[0].Move {
+		t.Fatalf("best move %v, want the first line %v", result.BestMoves[0], result.Lines[0].Move)
+	}
+}
@@ -0,0 +1,267 @@
+package gotack
+
+import "math"
+
+// initPlayers 记录根节点的行棋方编号，只对实现了 MultiPlayerBoard 的棋盘生效。
+func (e *Evaluator) initPlayers() {
+	mp, ok := e.Board.(MultiPlayerBoard)
+	e.multiPlayer = ok
+	if ok && !e.fixedRootPlayer {
+		e.rootPlayer = mp.CurrentPlayer()
+	}
+}
+
+// supportsMultiPlayer 检查棋盘是否实现了当前多人算法所需的接口。
+func (e *Evaluator) supportsMultiPlayer() bool {
+	if e.TreeType == BRS {
+		_, ok := e.Board.(BestReplyBoard)
+		return ok
+	}
+	return e.multiPlayer
+}
+
+// multiPlayerSearch 根据 TreeType 执行深度为 depth 的多人算法，返回根节点行棋方的分数与最佳走法。
+func (e *Evaluator) multiPlayerSearch(depth int) (float64, []Move) {
+	switch e.TreeType {
+	case MaxN:
+		scores, bestMoves := e.maxN(depth, e.EvalOptions)
+		if scores == nil {
+			return 0, nil
+		}
+		return scores[e.rootPlayer], bestMoves
+	case Paranoid:
+		return e.paranoid(depth, -math.MaxFloat64, math.MaxFloat64, e.EvalOptions)
+	default:
+		return e.brs(depth, -math.MaxFloat64, math.MaxFloat64, e.isRootPlayerToMove(e.Board.(MultiPlayerBoard)), e.EvalOptions)
+	}
+}
+
+// multiPlayerTree 检查 TreeType 是否为多人算法。
+func (e *Evaluator) multiPlayerTree() bool {
+	return e.TreeType == MaxN || e.TreeType == Paranoid || e.TreeType == BRS
+}
+
+// isRootPlayerToMove 返回多人棋盘当前是否轮到根节点的行棋方，用作 GetAllMoves 的 isMaxPlayer 参数。
+func (e *Evaluator) isRootPlayerToMove(board MultiPlayerBoard) bool {
+	return board.CurrentPlayer() == e.rootPlayer
+}
+
+// maxN 实现多人博弈的 Max^n 算法：每个玩家都选择使自己分数最大的走法，节点值为整个分数向量。
+// 返回节点的分数向量以及当前玩家的最佳走法。
+func (e *Evaluator) maxN(depth int, opts *EvalOptions) ([]float64, []Move) {
+	if e.stopping() {
+		return nil, nil
+	}
+	e.nodes++
+	e.reachDepth(e.Depth - depth)
+	board := e.Board.(MultiPlayerBoard)
+	if depth == 0 || e.Board.IsGameOver() {
+		opts.Extra["depth"] = e.Depth - depth
+		e.stats.LeafEvals++
+		return board.PlayerScores(*opts), nil
+	}
+
+	player := board.CurrentPlayer()
+	moves := e.legalMoves(e.Board, e.isRootPlayerToMove(board))
+	if len(moves) == 0 {
+		return e.noMovesScores(board, player, e.Depth-depth), nil
+	}
+
+	var bestScores []float64
+	var bestMoves []Move
+	for _, move := range moves {
+		e.Board.Move(move)
+		scores, _ := e.maxN(depth-1, opts)
+		e.Board.UndoMove(move)
+		if e.stopped {
+			break
+		}
+
+		if bestScores == nil || scores[player] > bestScores[player] {
+			bestScores = scores
+			bestMoves = []Move{move}
+		} else if scores[player] == bestScores[player] {
+			bestMoves = append(bestMoves, move)
+		}
+	}
+	if depth == e.Depth {
+		e.BestMoves = bestMoves // 只在顶层更新 BestMoves
+	}
+	return bestScores, bestMoves
+}
+
+// paranoid 实现多人博弈的 Paranoid 算法：假设其余所有玩家结成联盟共同最小化根节点行棋方的分数，
+// 从而把多人博弈转化为可以使用 Alpha-Beta 剪枝的两人博弈。返回根节点行棋方的分数。
+func (e *Evaluator) paranoid(depth int, alpha, beta float64, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	e.reachDepth(e.Depth - depth)
+	board := e.Board.(MultiPlayerBoard)
+	if depth == 0 || e.Board.IsGameOver() {
+		opts.Extra["depth"] = e.Depth - depth
+		e.stats.LeafEvals++
+		return board.PlayerScores(*opts)[e.rootPlayer], nil
+	}
+
+	rootToMove := e.isRootPlayerToMove(board)
+	moves := e.legalMoves(e.Board, rootToMove)
+	if len(moves) == 0 {
+		return e.noMovesValue(rootToMove, e.Depth-depth), nil
+	}
+
+	var bestMoves []Move
+	var eval float64
+	if rootToMove {
+		maxEval := math.Inf(-1)
+		for i, move := range moves {
+			e.Board.Move(move)
+			eval, _ = e.paranoid(depth-1, alpha, beta, opts)
+			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
+
+			if eval > maxEval {
+				maxEval = eval
+				bestMoves = []Move{move}
+			} else if eval == maxEval {
+				bestMoves = append(bestMoves, move)
+			}
+			alpha = math.Max(alpha, eval)
+			if beta <= alpha {
+				e.cutoff(i)
+				break
+			}
+		}
+		if depth == e.Depth {
+			e.BestMoves = bestMoves
+		}
+		return maxEval, bestMoves
+	} else {
+		minEval := math.Inf(1)
+		for i, move := range moves {
+			e.Board.Move(move)
+			eval, _ = e.paranoid(depth-1, alpha, beta, opts)
+			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
+
+			if eval < minEval {
+				minEval = eval
+				bestMoves = []Move{move}
+			} else if eval == minEval {
+				bestMoves = append(bestMoves, move)
+			}
+			beta = math.Min(beta, eval)
+			if beta <= alpha {
+				e.cutoff(i)
+				break
+			}
+		}
+		if depth == e.Depth {
+			e.BestMoves = bestMoves // 只在顶层更新 BestMoves
+		}
+		return minEval, bestMoves
+	}
+}
+
+// brs 实现多人博弈的 Best-Reply Search：根节点行棋方的每一层之后只展开一层对手层，
+// 该层中所有对手的全部走法合并在一起，只有对根节点行棋方威胁最大的一个对手走棋，其余对手视为停一手。
+// 对手层与根节点行棋方的层交替出现，因此可以使用 Alpha-Beta 剪枝，并能在相同深度下看到更多根节点行棋方的走法。
+// 棋盘需要实现 BestReplyBoard 接口。返回根节点行棋方的分数。
+func (e *Evaluator) brs(depth int, alpha, beta float64, isMaximizingPlayer bool, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	e.reachDepth(e.Depth - depth)
+	board := e.Board.(BestReplyBoard)
+	if depth == 0 || e.Board.IsGameOver() {
+		opts.Extra["depth"] = e.Depth - depth
+		e.stats.LeafEvals++
+		return board.PlayerScores(*opts)[e.rootPlayer], nil
+	}
+
+	var bestMoves []Move
+	var eval float64
+	if isMaximizingPlayer {
+		maxEval := math.Inf(-1)
+		board.SetCurrentPlayer(e.rootPlayer)
+		moves := e.legalMoves(e.Board, true)
+		if len(moves) == 0 {
+			return e.noMovesValue(true, e.Depth-depth), nil
+		}
+		for i, move := range moves {
+			e.Board.Move(move)
+			eval, _ = e.brs(depth-1, alpha, beta, false, opts)
+			e.Board.UndoMove(move)
+			board.SetCurrentPlayer(e.rootPlayer)
+			if e.stopped {
+				break
+			}
+
+			if eval > maxEval {
+				maxEval = eval
+				bestMoves = []Move{move}
+			} else if eval == maxEval {
+				bestMoves = append(bestMoves, move)
+			}
+			alpha = math.Max(alpha, eval)
+			if beta <= alpha {
+				e.cutoff(i)
+				break
+			}
+		}
+		if depth == e.Depth {
+			e.BestMoves = bestMoves
+		}
+		return maxEval, bestMoves
+	} else {
+		minEval := math.Inf(1)
+		current := board.CurrentPlayer()
+		tried := 0
+	opponents:
+		for player := 0; player < board.NumPlayers(); player++ {
+			if player == e.rootPlayer {
+				continue
+			}
+			board.SetCurrentPlayer(player)
+			e.stats.MoveGenerations++
+			for _, move := range e.Board.GetAllMoves(false) {
+				e.Board.Move(move)
+				board.SetCurrentPlayer(e.rootPlayer)
+				eval, _ = e.brs(depth-1, alpha, beta, true, opts)
+				e.Board.UndoMove(move)
+				board.SetCurrentPlayer(player)
+				if e.stopped {
+					break opponents
+				}
+
+				if eval < minEval {
+					minEval = eval
+					bestMoves = []Move{move}
+				} else if eval == minEval {
+					bestMoves = append(bestMoves, move)
+				}
+				beta = math.Min(beta, eval)
+				if beta <= alpha {
+					e.cutoff(tried)
+					break opponents
+				}
+				tried++
+			}
+		}
+		board.SetCurrentPlayer(current)
+		if math.IsInf(minEval, 1) {
+			if e.EvalOptions.NoMoves != NoMovesPass {
+				return e.noMovesValue(false, e.Depth-depth), nil
+			}
+			// 对手层本来就把未走棋的对手视为停一手，所有对手都没有走法时全部停一手
+			return e.brs(depth-1, alpha, beta, true, opts)
+		}
+		return minEval, bestMoves
+	}
+}
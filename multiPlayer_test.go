@@ -0,0 +1,140 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+// threeMoveScores 是 threeBoard 中每个走法给三名玩家加上的分数。
+var threeMoveScores = map[betMove][3]float64{
+	"safe":  {2, 0, 0},
+	"risky": {5, 0, 0},
+	"sx":    {0, 2, 0},
+	"sy":    {0, 1, 0},
+	"rx":    {0, 3, 0},
+	"ry":    {-5, 1, 0},
+	"z":     {0, 0, 1},
+}
+
+// threeBoard 是一个三人游戏：玩家 0 先选择 safe 或 risky，随后玩家 1 按玩家 0 的选择回应，走两步后结束，
+// 玩家 2 只有在 BRS 让它提前走棋时才会走出 z。每名玩家的分数为走过的走法分数之和。
+// 玩家 1 在 risky 之后自己选择 rx 时玩家 0 得 5 分，但若它与玩家 0 为敌则会选择 ry 让玩家 0 得 0 分，
+// 因此 Max^n 与多人 MCTS 应选择 risky，而 Paranoid 与 BRS 应选择 safe。
+type threeBoard struct {
+	path    []betMove
+	players []int // players[i] 为走出 path[i] 之前的行棋方
+	current int
+}
+
+func (b *threeBoard) Print() { fmt.Println(b.path) }
+
+func (b *threeBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() {
+		return nil
+	}
+	switch {
+	case b.current == 2:
+		return []Move{betMove("z")}
+	case b.current == 1 && len(b.path) > 0 && b.path[0] == "risky":
+		return []Move{betMove("rx"), betMove("ry")}
+	case b.current == 1:
+		return []Move{betMove("sx"), betMove("sy")}
+	}
+	return []Move{betMove("safe"), betMove("risky")}
+}
+
+func (b *threeBoard) Move(move Move) {
+	b.path = append(b.path, move.(betMove))
+	b.players = append(b.players, b.current)
+	b.current = (b.current + 1) % 3
+}
+
+func (b *threeBoard) UndoMove(move Move) {
+	b.current = b.players[len(b.players)-1]
+	b.path = b.path[:len(b.path)-1]
+	b.players = b.players[:len(b.players)-1]
+}
+
+func (b *threeBoard) IsGameOver() bool { return len(b.path) >= 2 }
+
+func (b *threeBoard) EvaluateFunc(opts EvalOptions) float64 { return b.PlayerScores(opts)[0] }
+
+func (b *threeBoard) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, m := range b.path {
+		for _, c := range []byte(m) {
+			h = (h ^ uint64(c)) * 1099511628211
+		}
+		h = (h ^ ' ') * 1099511628211
+	}
+	return h
+}
+
+func (b *threeBoard) Clone() Board {
+	return &threeBoard{
+		path:    append([]betMove(nil), b.path...),
+		players: append([]int(nil), b.players...),
+		current: b.current,
+	}
+}
+
+func (b *threeBoard) NumPlayers() int { return 3 }
+
+func (b *threeBoard) CurrentPlayer() int { return b.current }
+
+func (b *threeBoard) SetCurrentPlayer(player int) { b.current = player }
+
+func (b *threeBoard) PlayerScores(opts EvalOptions) []float64 {
+	scores := make([]float64, 3)
+	for _, m := range b.path {
+		for i, s := range threeMoveScores[m] {
+			scores[i] += s
+		}
+	}
+	return scores
+}
+
+func TestMultiPlayerSearch(t *testing.T) {
+	tests := []struct {
+		tt    GameTreeType
+		want  betMove
+		value float64
+	}{
+		{MaxN, "risky", 5},
+		{Paranoid, "safe", 2},
+		{BRS, "safe", 2},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprint(tc.tt), func(t *testing.T) {
+			result, err := NewEvaluator(tc.tt, NewEvaluatorOptions(WithBoard(&threeBoard{}), WithDepth(2))).Search()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.BestMoves) != 1 || result.BestMoves[0] != tc.want || result.Value != tc.value {
+				t.Fatalf("best moves %v with value %v, want %v with value %v", result.BestMoves, result.Value, tc.want, tc.value)
+			}
+		})
+	}
+}
+
+func TestMultiPlayerUCTBackpropagatesScoreVectors(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		e := NewEvaluator(UCT, NewEvaluatorOptions(
+			WithBoard(&threeBoard{}),
+			WithIterations(2000),
+			WithTimeLimit(0),
+			WithSeed(seed),
+		))
+		result, err := e.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 玩家 1 按自己的分数在 risky 之后选择 rx，玩家 0 因此选择 risky。
+		if len(result.BestMoves) == 0 || result.BestMoves[0] != betMove("risky") {
+			t.Fatalf("seed %d: best moves %v, want risky", seed, result.BestMoves)
+		}
+		if len(e.tree.TotalRewards) != 3 {
+			t.Fatalf("seed %d: root rewards %v, want one per player", seed, e.tree.TotalRewards)
+		}
+	}
+}
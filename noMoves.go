@@ -0,0 +1,112 @@
+package gotack
+
+import "errors"
+
+// NoMovesRule 表示局面未结束却没有合法走法时的处理方式。
+type NoMovesRule int
+
+const (
+	NoMovesEvaluate NoMovesRule = iota // 把局面当作叶节点，用 EvaluateFunc（多人算法为 PlayerScores）评估，默认值
+	NoMovesPass                        // 行棋方停一手，棋盘需要实现 PassBoard，否则按 NoMovesError 处理
+	NoMovesLoss                        // 行棋方判负，按距离根节点的步数记为胜负评估值
+	NoMovesDraw                        // 判为和棋，评估值与重复局面相同
+	NoMovesError                       // 中止搜索并返回 ErrNoLegalMoves
+)
+
+var (
+	// ErrNoLegalMoves 表示搜索遇到了未结束却没有合法走法的局面，且 NoMoves 规则无法处理。
+	ErrNoLegalMoves = errors.New("gotack: position has no legal moves but is not game over")
+
+	// ErrUnsupportedBoard 表示棋盘没有实现所选算法需要的接口。
+	ErrUnsupportedBoard = errors.New("gotack: board does not support the selected tree type")
+
+	// ErrUnsupportedTreeType 表示不支持的博弈树类型。
+	ErrUnsupportedTreeType = errors.New("gotack: unsupported tree type")
+
+	// ErrEvalBoundsRequired 表示 UCT 或 ISMCTS 的模拟可能得到胜负结果，却没有通过 WithEvalBounds 设置有限的评估值界限，
+	// 无法把胜负换算为与 EvaluateFunc 同一尺度的奖励。
+	ErrEvalBoundsRequired = errors.New("gotack: finite eval bounds are required to score game results in MCTS")
+)
+
+// legalMoves 返回 board 上行棋方的合法走法。局面未结束却没有合法走法时，
+// 若 NoMoves 规则为 NoMovesPass 且棋盘实现了 PassBoard，则返回只含停着的切片，否则返回空切片，
+// 调用者此时应使用 noMovesValue 作为局面的评估值。
+func (e *Evaluator) legalMoves(board Board, isMaxPlayer bool) []Move {
+	e.stats.MoveGenerations++
+	moves := board.GetAllMoves(isMaxPlayer)
+	if len(moves) > 0 || e.EvalOptions.NoMoves != NoMovesPass {
+		return moves
+	}
+	if pb, ok := board.(PassBoard); ok && !board.IsGameOver() {
+		return []Move{pb.PassMove(isMaxPlayer)}
+	}
+	return moves
+}
+
+// noMovesValue 返回没有合法走法且不能停一手的当前局面 e.Board 以最大化玩家视角的评估值，ply 为距离根节点的步数。
+// 多人算法中 isMaxPlayer 表示行棋方是否为根节点的行棋方，评估值为根节点行棋方的分数。
+// NoMovesError（以及棋盘未实现 PassBoard 的 NoMovesPass）会记录 ErrNoLegalMoves 并返回 0，搜索结束后由 Search 返回该错误。
+func (e *Evaluator) noMovesValue(isMaxPlayer bool, ply int) float64 {
+	switch e.EvalOptions.NoMoves {
+	case NoMovesEvaluate:
+		if mp, ok := e.Board.(MultiPlayerBoard); ok && e.multiPlayerTree() {
+			e.stats.LeafEvals++
+			return mp.PlayerScores(*e.EvalOptions)[e.rootPlayer]
+		}
+		return e.evaluateLeaf(isMaxPlayer, ply, e.EvalOptions)
+	case NoMovesLoss:
+		return resultValue(Loss, isMaxPlayer, ply)
+	case NoMovesDraw:
+		return e.drawScore()
+	}
+	e.fail(ErrNoLegalMoves)
+	return 0
+}
+
+// noMovesScores 是 noMovesValue 的 Max^n 版本，返回当前行棋方 player 没有合法走法时每个玩家的分数。
+// NoMovesLoss 时该玩家记为负、其余玩家记为胜；NoMovesDraw 时所有玩家的分数为 0，根节点行棋方计入 Contempt。
+func (e *Evaluator) noMovesScores(board MultiPlayerBoard, player, ply int) []float64 {
+	scores := make([]float64, board.NumPlayers())
+	switch e.EvalOptions.NoMoves {
+	case NoMovesEvaluate:
+		e.stats.LeafEvals++
+		return board.PlayerScores(*e.EvalOptions)
+	case NoMovesLoss:
+		for i := range scores {
+			scores[i] = resultValue(Win, true, ply)
+		}
+		scores[player] = resultValue(Loss, true, ply)
+		return scores
+	case NoMovesDraw:
+		scores[e.rootPlayer] = 0 - e.EvalOptions.Contempt
+		return scores
+	}
+	e.fail(ErrNoLegalMoves)
+	return scores
+}
+
+// fail 记录搜索过程中遇到的第一个错误。
+func (e *Evaluator) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// rootWithoutMoves 在搜索开始前检查根局面。根局面未结束、不是机会节点、没有合法走法且不能停一手时，
+// 返回按 NoMoves 规则得到的评估值与 true，此时不需要再进行搜索。
+func (e *Evaluator) rootWithoutMoves() (float64, bool) {
+	if e.TreeType == SimultaneousUCT || e.Board.IsGameOver() {
+		return 0, false
+	}
+	if _, ok := chanceOutcomes(e.Board); ok {
+		return 0, false
+	}
+	isMaxPlayer := e.EvalOptions.IsMaxPlayer
+	if e.multiPlayer {
+		isMaxPlayer = true
+	}
+	if len(e.legalMoves(e.Board, isMaxPlayer)) > 0 {
+		return 0, false
+	}
+	return e.noMovesValue(isMaxPlayer, 0), true
+}
@@ -0,0 +1,125 @@
+package gotack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// stuckBoard 是一个两步的小游戏：最大化玩家走 b 后游戏结束，评估为 1；
+// 走 a 后最小化玩家没有合法走法，游戏却没有结束，该局面的 EvaluateFunc 为 5。
+type stuckBoard struct {
+	path []string
+}
+
+func (b *stuckBoard) key() string { return fmt.Sprint(b.path) }
+
+func (b *stuckBoard) Print() { fmt.Println(b.key()) }
+
+func (b *stuckBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if len(b.path) == 0 {
+		return []Move{betMove("a"), betMove("b")}
+	}
+	return nil
+}
+
+func (b *stuckBoard) Move(move Move) { b.path = append(b.path, move.String()) }
+
+func (b *stuckBoard) UndoMove(move Move) { b.path = b.path[:len(b.path)-1] }
+
+func (b *stuckBoard) IsGameOver() bool { return len(b.path) == 1 && b.path[0] == "b" }
+
+func (b *stuckBoard) EvaluateFunc(opts EvalOptions) float64 {
+	switch b.key() {
+	case "[a]":
+		return 5
+	case "[b]":
+		return 1
+	}
+	return 0
+}
+
+func (b *stuckBoard) Hash() uint64 { return uint64(len(b.key())) }
+
+func (b *stuckBoard) Clone() Board { return &stuckBoard{path: append([]string(nil), b.path...)} }
+
+func TestNoMovesRules(t *testing.T) {
+	if opts := NewEvaluatorOptions(); opts.NoMoves != NoMovesEvaluate {
+		t.Fatalf("default rule %d, want NoMovesEvaluate", opts.NoMoves)
+	}
+	tests := []struct {
+		rule  NoMovesRule
+		want  betMove
+		value float64
+		err   error
+	}{
+		{NoMovesEvaluate, "a", 5, nil},
+		{NoMovesLoss, "a", MateScore - 1, nil},
+		{NoMovesDraw, "b", 1, nil},
+		{NoMovesError, "", 0, ErrNoLegalMoves},
+		// stuckBoard 没有实现 PassBoard，停一手无法进行，按 NoMovesError 处理。
+		{NoMovesPass, "", 0, ErrNoLegalMoves},
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS, Expectimax, UCT} {
+		for _, tc := range tests {
+			t.Run(fmt.Sprintf("%d/rule=%d", tt, tc.rule), func(t *testing.T) {
+				result, err := NewEvaluator(tt, NewEvaluatorOptions(
+					WithBoard(&stuckBoard{}),
+					WithDepth(2),
+					WithNoMovesRule(tc.rule),
+					WithEvalBounds(-10, 10),
+					WithIterations(500),
+					WithTimeLimit(0),
+				)).Search()
+				if tc.err != nil {
+					if !errors.Is(err, tc.err) {
+						t.Fatalf("error %v, want %v", err, tc.err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.BestMoves) != 1 || result.BestMoves[0] != tc.want {
+					t.Fatalf("best moves %v, want %v", result.BestMoves, tc.want)
+				}
+				if tt != UCT && result.Value != tc.value {
+					t.Fatalf("value %v, want %v", result.Value, tc.value)
+				}
+			})
+		}
+	}
+}
+
+func TestNoMovesAtRoot(t *testing.T) {
+	tests := []struct {
+		rule     NoMovesRule
+		value    float64
+		wantMove Move
+	}{
+		// 默认的 NoMovesEvaluate 把根局面当作叶节点，没有最佳走法。
+		{NoMovesEvaluate, 0, nil},
+		{NoMovesLoss, -MateScore, nil},
+		// 根节点行棋方停一手后，对手唯一的走法让根节点行棋方在第 3 步获胜。
+		{NoMovesPass, MateScore - 3, betMove("pass")},
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS} {
+		for _, tc := range tests {
+			result, err := NewEvaluator(tt, NewEvaluatorOptions(
+				WithBoard(&passGame{}),
+				WithDepth(3),
+				WithNoMovesRule(tc.rule),
+			)).Search()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var move Move
+			if len(result.BestMoves) > 0 {
+				move = result.BestMoves[0]
+			}
+			if result.Value != tc.value || move != tc.wantMove {
+				t.Errorf("%d/rule=%d: value %v with move %v, want %v with move %v", tt, tc.rule, result.Value, move, tc.value, tc.wantMove)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countingPickBoard 在 pickBoard 上统计 Move 的调用次数。
+type countingPickBoard struct {
+	*pickBoard
+	moves *int
+}
+
+func (b countingPickBoard) Move(move Move) {
+	*b.moves++
+	b.pickBoard.Move(move)
+}
+
+func (b countingPickBoard) Clone() Board {
+	return countingPickBoard{b.pickBoard.Clone().(*pickBoard), b.moves}
+}
+
+func TestMaxNodesHardAtDepthOne(t *testing.T) {
+	scores := make([]float64, 40)
+	for i := range scores {
+		scores[i] = float64(i)
+	}
+	// 根节点计 1 个节点，之后每个子节点计 1 个节点；PVS 对分数更高的子节点先做空窗口搜索再重新搜索，每个计 2 个节点。
+	// 预算耗尽时使用已完成的子节点中分数最高的一个，一个子节点都没有完成时退而使用第一个合法走法。
+	cost := map[GameTreeType]int64{AlphaBeta: 1, PVS: 2, Expectimax: 1, Star1: 1}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS, Expectimax, Star1} {
+		for _, budget := range []int64{1, 10, 40} {
+			t.Run(fmt.Sprintf("%d/%d", tt, budget), func(t *testing.T) {
+				moves := 0
+				e := NewEvaluator(tt, NewEvaluatorOptions(
+					WithBoard(countingPickBoard{newPickBoard(3, scores...), &moves}),
+					WithDepth(3),
+					WithMaxNodes(budget),
+				))
+				result, err := e.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Stats.Nodes > budget || int64(moves) > budget {
+					t.Fatalf("visited %d nodes with %d moves, budget %d", result.Stats.Nodes, moves, budget)
+				}
+				want := pickMove(max((budget-2)/cost[tt], 0))
+				if len(result.BestMoves) == 0 || result.BestMoves[0] != want {
+					t.Fatalf("best moves %v, want %v", result.BestMoves, want)
+				}
+			})
+		}
+	}
+}
+
+func TestMaxNodesIgnoresTimeLimitWhenDeepening(t *testing.T) {
+	tests := []struct {
+		opts []EvalOption
+		want time.Duration
+	}{
+		{[]EvalOption{WithTimeLimit(10)}, forever},
+		{[]EvalOption{WithTimeLimit(10), WithMoveTime(time.Hour)}, time.Hour},
+	}
+	for _, tt := range tests {
+		opts := append([]EvalOption{WithBoard(newPickBoard(2, 1, 2, 3)), WithDepth(2), WithMaxNodes(100)}, tt.opts...)
+		e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(opts...))
+		if _, err := e.Search(); err != nil {
+			t.Fatal(err)
+		}
+		if e.tm.soft != tt.want || e.tm.hard != tt.want {
+			t.Errorf("soft %v hard %v, want %v", e.tm.soft, e.tm.hard, tt.want)
+		}
+	}
+}
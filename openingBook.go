@@ -0,0 +1,261 @@
+package gotack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BookSelectionType 表示从开局库中选择走法的方式。
+type BookSelectionType int
+
+const (
+	BookWeighted BookSelectionType = iota // 按权重比例随机选择，使开局富于变化
+	BookBest                              // 总是选择权重最大的走法
+)
+
+// BookMove 表示开局库中某个局面下的一个候选走法。
+type BookMove struct {
+	// Move 为走法的 Move.String()。
+	Move string
+	// Weight 为走法的权重，权重越大越常被选中，小于等于 0 的走法不会被选中。
+	Weight float64
+}
+
+// OpeningBook 是以 Board.Hash() 为键的开局库，每个局面对应若干带权重的走法。
+// 开局库以文本格式保存，每行依次为 16 位十六进制哈希、权重与走法，以 # 开头的行为注释。
+// 通过 WithOpeningBook 设置后，GetBestMove 会优先使用开局库中的走法，局面不在库中时再进行搜索。
+type OpeningBook struct {
+	entries map[uint64][]BookMove
+}
+
+// NewOpeningBook 创建一个空的开局库。
+func NewOpeningBook() *OpeningBook {
+	return &OpeningBook{entries: make(map[uint64][]BookMove)}
+}
+
+// Add 为局面 hash 的走法 move 增加权重 weight，走法不存在时新建。
+func (b *OpeningBook) Add(hash uint64, move string, weight float64) {
+	moves := b.entries[hash]
+	for i := range moves {
+		if moves[i].Move == move {
+			moves[i].Weight += weight
+			return
+		}
+	}
+	b.entries[hash] = append(moves, BookMove{Move: move, Weight: weight})
+}
+
+// Moves 返回局面 hash 的全部候选走法，按权重从大到小排列，权重相同时按走法排列。
+func (b *OpeningBook) Moves(hash uint64) []BookMove {
+	moves := append([]BookMove(nil), b.entries[hash]...)
+	sort.SliceStable(moves, func(i, j int) bool {
+		if moves[i].Weight != moves[j].Weight {
+			return moves[i].Weight > moves[j].Weight
+		}
+		return moves[i].Move < moves[j].Move
+	})
+	return moves
+}
+
+// Len 返回开局库中的局面数。
+func (b *OpeningBook) Len() int {
+	return len(b.entries)
+}
+
+// AddGame 将一局棋记录加入开局库：从 board 出发依次应用 moves 中的前 maxPly 步，
+// 为每一步走前的局面记录该走法并增加权重 weight。maxPly 小于等于 0 时记录全部走法。
+// 调用者可以按对局结果设置权重，例如胜局为 1、和局为 0.5。board 不会被修改。
+func (b *OpeningBook) AddGame(board Board, moves []Move, maxPly int, weight float64) {
+	board = board.Clone()
+	for ply, move := range moves {
+		if maxPly > 0 && ply >= maxPly {
+			break
+		}
+		b.Add(board.Hash(), move.String(), weight)
+		board.Move(move)
+	}
+}
+
+// BuildOpeningBook 通过自对弈生成开局库：从 opts.Board 出发进行 games 局对弈，每局只走前 maxPly 步，
+// 每一步都用 treeType 搜索当前局面，从最佳走法中随机选择一个走下去，并为该走法增加 1 的权重。
+// 同一局面的搜索结果会被缓存，不同对局之间的变化来自最佳走法之间的随机选择与机会节点的采样。
+// 参数:
+//   - treeType: 自对弈使用的搜索算法。
+//   - opts: 评估选项，每次搜索都以其副本进行（包括 Extra），Seed 决定整个生成过程的随机性。
+//     多人棋盘每一步的 IsMaxPlayer 取决于当前行棋方是否为 opts.Board 的行棋方。
+//   - games: 自对弈的对局数。
+//   - maxPly: 每局记录的最大步数。
+//
+// 返回值:
+//   - *OpeningBook: 生成的开局库。
+//   - error: 任意一次搜索出错时返回该错误。
+func BuildOpeningBook(treeType GameTreeType, opts *EvalOptions, games, maxPly int) (*OpeningBook, error) {
+	book := NewOpeningBook()
+	rng := opts.newRand()
+	cache := make(map[uint64][]Move)
+	rootPlayer := 0
+	if mp, ok := opts.Board.(MultiPlayerBoard); ok {
+		rootPlayer = mp.CurrentPlayer()
+	}
+	for game := 0; game < games; game++ {
+		board := opts.Board.Clone()
+		isMaxPlayer := opts.IsMaxPlayer
+		for ply := 0; ply < maxPly && !board.IsGameOver(); {
+			if outcomes, ok := chanceOutcomes(board); ok {
+				board.Move(sampleOutcome(outcomes, rng))
+				continue
+			}
+			if mp, ok := board.(MultiPlayerBoard); ok {
+				isMaxPlayer = mp.CurrentPlayer() == rootPlayer
+			}
+			hash := board.Hash()
+			bestMoves, ok := cache[hash]
+			if !ok {
+				searchOpts := cloneOptions(opts, board.Clone())
+				searchOpts.IsMaxPlayer = isMaxPlayer
+				searchOpts.Seed = rng.Int63()
+				searchOpts.RandSource = nil
+				searchOpts.OpeningBook = nil
+				result, err := NewEvaluator(treeType, searchOpts).Search()
+				if err != nil {
+					return nil, err
+				}
+				bestMoves = result.BestMoves
+				cache[hash] = bestMoves
+			}
+			if len(bestMoves) == 0 {
+				break
+			}
+			move := bestMoves[rng.Intn(len(bestMoves))]
+			book.Add(hash, move.String(), 1)
+			board.Move(move)
+			isMaxPlayer = !isMaxPlayer
+			ply++
+		}
+	}
+	return book, nil
+}
+
+// Save 将开局库以文本格式写入 w，局面按哈希升序排列，同一局面的走法按权重从大到小排列。
+func (b *OpeningBook) Save(w io.Writer) error {
+	hashes := make([]uint64, 0, len(b.entries))
+	for hash := range b.entries {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i] < hashes[j]
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# gotack opening book: hash weight move")
+	for _, hash := range hashes {
+		for _, move := range b.Moves(hash) {
+			fmt.Fprintf(bw, "%016x %s %s\n", hash, strconv.FormatFloat(move.Weight, 'g', -1, 64), move.Move)
+		}
+	}
+	return bw.Flush()
+}
+
+// SaveFile 将开局库保存到指定路径的文件。
+func (b *OpeningBook) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := b.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadOpeningBook 从 r 中读取由 Save 写入的开局库，同一局面的相同走法会合并权重。
+func LoadOpeningBook(r io.Reader) (*OpeningBook, error) {
+	book := NewOpeningBook()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.SplitN(text, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("gotack: opening book line %d: expected hash, weight and move", line)
+		}
+		hash, err := strconv.ParseUint(fields[0], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("gotack: opening book line %d: %w", line, err)
+		}
+		weight, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("gotack: opening book line %d: %w", line, err)
+		}
+		book.Add(hash, fields[2], weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// LoadOpeningBookFile 从指定路径的文件中读取开局库。
+func LoadOpeningBookFile(path string) (*OpeningBook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadOpeningBook(f)
+}
+
+// bookMove 在根局面查询 WithOpeningBook 设置的开局库，返回按 BookSelection 选出的合法走法。
+// 开局库中没有当前局面、根节点为机会节点或库中走法都不合法时返回 nil。
+func (e *Evaluator) bookMove() Move {
+	book := e.EvalOptions.OpeningBook
+	if book == nil {
+		return nil
+	}
+	if _, ok := chanceOutcomes(e.Board); ok {
+		return nil
+	}
+	candidates := book.Moves(e.Board.Hash())
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	isMaxPlayer := e.EvalOptions.IsMaxPlayer
+	if e.multiPlayer {
+		isMaxPlayer = true
+	}
+	legal := make(map[string]Move)
+	for _, move := range e.Board.GetAllMoves(isMaxPlayer) {
+		legal[move.String()] = move
+	}
+	var moves []Move
+	var weights []float64
+	total := 0.0
+	for _, candidate := range candidates {
+		if move, ok := legal[candidate.Move]; ok && candidate.Weight > 0 {
+			moves = append(moves, move)
+			weights = append(weights, candidate.Weight)
+			total += candidate.Weight
+		}
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+	if e.EvalOptions.BookSelection == BookBest {
+		return moves[0]
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return moves[sampleIndex(weights, e.rng)]
+}
@@ -0,0 +1,140 @@
+package gotack
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpeningBookSaveLoadRoundTrip(t *testing.T) {
+	book := NewOpeningBook()
+	book.Add(0xbeef, "e2e4", 3)
+	book.Add(0xbeef, "d2d4", 1.5)
+	book.Add(0xbeef, "e2e4", 2)
+	book.Add(1, "pass move", 0.25)
+
+	var saved bytes.Buffer
+	if err := book.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	want := "# gotack opening book: hash weight move\n" +
+		"0000000000000001 0.25 pass move\n" +
+		"000000000000beef 5 e2e4\n" +
+		"000000000000beef 1.5 d2d4\n"
+	if saved.String() != want {
+		t.Fatalf("saved\n%s\nwant\n%s", saved.String(), want)
+	}
+
+	loaded, err := LoadOpeningBook(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "book.txt")
+	if err := loaded.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadOpeningBookFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*OpeningBook{loaded, reloaded} {
+		if b.Len() != book.Len() {
+			t.Fatalf("%d positions, want %d", b.Len(), book.Len())
+		}
+		for _, hash := range []uint64{0xbeef, 1} {
+			if got, want := fmt.Sprint(b.Moves(hash)), fmt.Sprint(book.Moves(hash)); got != want {
+				t.Fatalf("moves at %x: %s, want %s", hash, got, want)
+			}
+		}
+	}
+
+	if _, err := LoadOpeningBook(bytes.NewReader([]byte("beef 1\n"))); err == nil {
+		t.Fatal("loaded a line without a move")
+	}
+}
+
+func TestOpeningBookSelection(t *testing.T) {
+	board := newPickBoard(2, 1, 2, 3)
+	book := NewOpeningBook()
+	book.Add(board.Hash(), "9", 100) // 不合法的走法不会被选中
+	book.Add(board.Hash(), "0", 3)
+	book.Add(board.Hash(), "2", 1)
+	book.Add(board.Hash(), "1", 0) // 权重为 0 的走法不会被选中
+
+	result, err := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(board), WithOpeningBook(book, BookBest))).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.FromBook || len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(0) {
+		t.Fatalf("book best %v (from book %v), want the legal move with the largest weight", result.BestMoves, result.FromBook)
+	}
+
+	counts := make(map[Move]int)
+	for seed := int64(1); seed <= 400; seed++ {
+		e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(board), WithOpeningBook(book, BookWeighted), WithSeed(seed)))
+		result, err := e.Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[result.BestMoves[0]]++
+	}
+	if len(counts) != 2 || counts[pickMove(0)] < 250 || counts[pickMove(0)] > 350 {
+		t.Fatalf("weighted book moves %v, want about 3:1 between moves 0 and 2", counts)
+	}
+
+	// 走出一步后局面不在库中，改为搜索。
+	board.Move(pickMove(0))
+	result, err = NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(board), WithDepth(1), WithIsMaxPlayer(false), WithOpeningBook(book, BookBest))).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FromBook || len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(0) {
+		t.Fatalf("best moves %v (from book %v), want a searched move", result.BestMoves, result.FromBook)
+	}
+}
+
+func TestOpeningBookAddGame(t *testing.T) {
+	board := newPickBoard(3, 1, 2, 3)
+	book := NewOpeningBook()
+	book.AddGame(board, []Move{pickMove(2), pickMove(0), pickMove(1)}, 2, 1)
+	book.AddGame(board, []Move{pickMove(2), pickMove(1)}, 0, 0.5)
+	if len(board.path) != 0 {
+		t.Fatalf("AddGame moved the board to %v", board.path)
+	}
+	if book.Len() != 2 {
+		t.Fatalf("%d positions, want 2 (maxPly limits the first game)", book.Len())
+	}
+	if got := fmt.Sprint(book.Moves(board.Hash())); got != "[{2 1.5}]" {
+		t.Fatalf("root moves %s, want move 2 with weight 1.5", got)
+	}
+	board.Move(pickMove(2))
+	if got := fmt.Sprint(book.Moves(board.Hash())); got != "[{0 1} {1 0.5}]" {
+		t.Fatalf("moves after 2: %s", got)
+	}
+}
+
+func TestBuildOpeningBookMultiPlayer(t *testing.T) {
+	board := &threeBoard{}
+	book, err := BuildOpeningBook(MaxN, NewEvaluatorOptions(WithBoard(board), WithDepth(2)), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 每名玩家都按自己的分数选择：玩家 0 选择 risky，玩家 1 随后选择对自己更好的 rx。
+	if got := fmt.Sprint(book.Moves(board.Hash())); got != "[{risky 3}]" {
+		t.Fatalf("root moves %s, want risky three times", got)
+	}
+	board.Move(betMove("risky"))
+	if got := fmt.Sprint(book.Moves(board.Hash())); got != "[{rx 3}]" {
+		t.Fatalf("player 1 moves %s, want rx three times", got)
+	}
+
+	board.UndoMove(betMove("risky"))
+	result, err := NewEvaluator(MaxN, NewEvaluatorOptions(WithBoard(board), WithOpeningBook(book, BookBest))).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.FromBook || result.BestMoves[0] != betMove("risky") {
+		t.Fatalf("best moves %v (from book %v), want the book move risky", result.BestMoves, result.FromBook)
+	}
+}
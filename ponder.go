@@ -0,0 +1,91 @@
+package gotack
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoPonderMove 表示最近一次搜索的主要变例中没有预测的对手应着，无法开始后台思考。
+var ErrNoPonderMove = errors.New("gotack: principal variation has no expected reply to ponder on")
+
+// Ponder 表示一次在对手思考时间内进行的后台搜索。
+// 后台搜索在预测局面（己方走出最佳走法、对手走出预测应着之后的局面）的克隆上进行，不受时间限制：
+// AlphaBeta/PVS 持续迭代加深，MCTS 类算法持续扩展同一棵搜索树。
+// 对手走出预测应着时调用 PonderHit，后台搜索转为正式搜索并沿用已经积累的结果；否则调用 PonderMiss 取消搜索。
+type Ponder struct {
+	// Move 为预测的对手应着。
+	Move Move
+
+	owner     *Evaluator
+	evaluator *Evaluator // 在预测局面上搜索的评估器
+	stop      atomic.Bool
+	done      chan struct{}
+	result    *SearchResult
+	err       error
+}
+
+// StartPonder 根据最近一次搜索的主要变例开始后台思考。
+// 需要先调用 Search 或 GetBestMove，且 Result.PV 至少包含己方的最佳走法与对手的预测应着。
+//
+// 返回值:
+//   - *Ponder: 后台思考的句柄，调用者必须在之后调用 PonderHit 或 PonderMiss 之一。
+//   - error: 主要变例中没有预测应着时返回 ErrNoPonderMove。
+//
+// 后台搜索的对局历史会追加根局面与最佳走法之后的局面，以便正确检测重复局面。e.Board 不会被修改。
+func (e *Evaluator) StartPonder() (*Ponder, error) {
+	if e.Result == nil || len(e.Result.PV) < 2 {
+		return nil, ErrNoPonderMove
+	}
+	bestMove, reply := e.Result.PV[0], e.Result.PV[1]
+
+	board := e.Board.Clone()
+	history := append(append([]uint64(nil), e.EvalOptions.History...), board.Hash())
+	board.Move(bestMove)
+	history = append(history, board.Hash())
+	board.Move(reply)
+
+	p := &Ponder{
+		Move:  reply,
+		owner: e,
+		done:  make(chan struct{}),
+	}
+	p.evaluator = e.spawn(board, &p.stop)
+	p.evaluator.EvalOptions.History = history
+	go func() {
+		defer close(p.done)
+		p.result, p.err = p.evaluator.Search()
+	}()
+	return p, nil
+}
+
+// PonderHit 在对手走出了预测应着时调用，后台搜索转为正式搜索。
+// 思考时间从此刻开始，按调用时 e.EvalOptions 中的 Clock、MoveTime 或 TimeLimit 分配（可以在调用前更新 Clock），
+// 到达软限制或搜索自然结束后返回结果。返回的结果同时保存在 e.Result 中，其根局面为预测局面。
+func (p *Ponder) PonderHit() (*SearchResult, error) {
+	soft := newTimeManager(p.owner.EvalOptions).soft
+	if soft < forever {
+		timer := time.NewTimer(soft)
+		select {
+		case <-p.done:
+		case <-timer.C:
+			p.stop.Store(true)
+			<-p.done
+		}
+		timer.Stop()
+	} else {
+		<-p.done
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.owner.Result = p.result
+	p.owner.BestMoves = p.result.BestMoves
+	return p.result, nil
+}
+
+// PonderMiss 在对手没有走出预测应着时调用，取消后台搜索并等待其结束，之后应在实际局面上重新搜索。
+func (p *Ponder) PonderMiss() {
+	p.stop.Store(true)
+	<-p.done
+}
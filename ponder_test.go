@@ -0,0 +1,98 @@
+package gotack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStartPonderNeedsExpectedReply(t *testing.T) {
+	e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(newPickBoard(2, 1, 2, 3)), WithDepth(1)))
+	if _, err := e.StartPonder(); !errors.Is(err, ErrNoPonderMove) {
+		t.Fatalf("error %v before any search, want ErrNoPonderMove", err)
+	}
+	if _, err := e.Search(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.StartPonder(); !errors.Is(err, ErrNoPonderMove) {
+		t.Fatalf("error %v with a one-move PV, want ErrNoPonderMove", err)
+	}
+}
+
+func TestPonderHit(t *testing.T) {
+	board := newPickBoard(4, 1, 2, 3)
+	e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(board), WithDepth(2)))
+	if _, err := e.Search(); err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.StartPonder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Move != pickMove(0) {
+		t.Fatalf("pondering on %v, want the expected reply 0", p.Move)
+	}
+	result, err := p.PonderHit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 预测局面为 [2 0]，其后双方同样选择 2 与 0，总分为 3+1+3+1。
+	if len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(2) || result.Value != 8 {
+		t.Fatalf("best moves %v with value %v, want 2 with value 8", result.BestMoves, result.Value)
+	}
+	if e.Result != result || len(board.path) != 0 {
+		t.Fatalf("PonderHit did not store the result or modified the board %v", board.path)
+	}
+}
+
+func TestPonderHitAndMissStopBackgroundSearch(t *testing.T) {
+	for _, hit := range []bool{true, false} {
+		t.Run(fmt.Sprintf("hit=%v", hit), func(t *testing.T) {
+			board := newPickBoard(1000, 1, 2, 3)
+			e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(WithBoard(board), WithDepth(64), WithMoveTime(20*time.Millisecond)))
+			first, err := e.Search()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := e.StartPonder()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan *SearchResult)
+			go func() {
+				if !hit {
+					p.PonderMiss()
+					done <- nil
+					return
+				}
+				result, err := p.PonderHit()
+				if err != nil {
+					t.Error(err)
+				}
+				done <- result
+			}()
+			var result *SearchResult
+			select {
+			case result = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("background search did not stop")
+			}
+
+			if len(board.path) != 0 {
+				t.Fatalf("pondering modified the board to %v", board.path)
+			}
+			if !hit {
+				// 未命中时保留原来的结果，之后应在实际局面上重新搜索。
+				if e.Result != first {
+					t.Fatal("PonderMiss replaced the result")
+				}
+				return
+			}
+			if result == nil || e.Result != result || len(result.BestMoves) == 0 || result.Stats.MaxDepth == 0 {
+				t.Fatalf("ponder hit result %+v, want a completed search of the predicted position", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package gotack
+
+import "math"
+
+// pnInfinity 表示证明数或反证数为无穷大。
+const pnInfinity = math.MaxInt32
+
+// SolveResult 表示证明数搜索的结果。
+type SolveResult struct {
+	// Result 为根节点行棋方的结果：Win 表示已证明必胜，Loss 表示已证明无法取胜（包括和棋），
+	// Unknown 表示在限制内未能得出结论。
+	Result GameResult
+	// Move 为证明必胜时的取胜走法，否则为 nil。
+	Move Move
+	// Nodes 为搜索过程中展开的节点数。
+	Nodes int
+}
+
+// value 将证明结果转换为以最大化玩家视角的评估值：必胜为 1，无法取胜为 -1，未知为 0。
+func (r SolveResult) value(isMaxPlayer bool) float64 {
+	value := 0.0
+	switch r.Result {
+	case Win:
+		value = 1
+	case Loss:
+		value = -1
+	}
+	if !isMaxPlayer {
+		value = -value
+	}
+	return value
+}
+
+// pnNode 是证明数搜索树中的节点。OR 节点由进攻方（根节点行棋方）走棋，AND 节点由防守方走棋。
+type pnNode struct {
+	move        Move
+	parent      *pnNode
+	children    []*pnNode
+	isOr        bool
+	isMaxPlayer bool
+	pn, dn      int
+}
+
+// solver 保存一次证明数搜索的状态。
+type solver struct {
+	e        *Evaluator
+	attacker bool // 进攻方是否为最大化玩家
+	nodes    int
+	maxNodes int
+	table    map[uint64]pnEntry
+	path     map[uint64]bool
+	depth    int // DFPN 当前的搜索深度
+}
+
+// pnEntry 是 DFPN 置换表中的一项。
+type pnEntry struct {
+	pn, dn int
+}
+
+// Solve 使用证明数搜索判断根节点行棋方能否强制取胜，并返回证明结果与取胜走法。
+// TreeType 为 ProofNumber 时使用经典的最佳优先证明数搜索，否则使用以 Board.Hash() 为键的置换表的 DFPN。
+// 展开节点数受 Iterations 与 MaxNodes 中较小者限制（0 表示不限制），同时受 TimeLimit、MoveTime 或 Clock 分配的时间限制。
+// 终局结果优先使用 ResultBoard 接口，没有明确结果时和棋与失败同样视为进攻方未能取胜。
+// 走法由 legalMoves 生成，非终局却没有合法走法的局面按 NoMoves 规则处理，NoMovesPass 时以停着继续搜索。
+func (e *Evaluator) Solve() SolveResult {
+	opts := e.EvalOptions
+	s := &solver{
+		e:        e,
+		attacker: opts.IsMaxPlayer,
+		maxNodes: opts.Iterations,
+	}
+	e.tm = e.newTimeManager()
+	if s.maxNodes <= 0 {
+		s.maxNodes = math.MaxInt
+	}
+	if budget := opts.nodeBudget(); budget > 0 {
+		s.maxNodes = min(s.maxNodes, int(budget))
+	}
+
+	var result SolveResult
+	if e.TreeType == ProofNumber {
+		result = s.pns()
+	} else {
+		result = s.dfpn()
+	}
+	e.nodes = int64(result.Nodes)
+	if s.table != nil {
+		e.stats.TreeSize = int64(len(s.table))
+	}
+	return result
+}
+
+// terminalResult 返回终局局面以当前行棋方视角的结果。
+// 棋盘未实现 ResultBoard 时根据 EvaluateFunc 的符号判断。
+func (e *Evaluator) terminalResult(board Board, isMaxPlayer bool) GameResult {
+	e.stats.LeafEvals++
+	if rb, ok := board.(ResultBoard); ok {
+		return rb.Result()
+	}
+	return e.evaluatedResult(board, isMaxPlayer)
+}
+
+// evaluatedResult 根据 EvaluateFunc 的符号返回以当前行棋方视角的结果。
+func (e *Evaluator) evaluatedResult(board Board, isMaxPlayer bool) GameResult {
+	value := board.EvaluateFunc(*e.EvalOptions)
+	if !isMaxPlayer {
+		value = -value
+	}
+	switch {
+	case value > 0:
+		return Win
+	case value < 0:
+		return Loss
+	default:
+		return Draw
+	}
+}
+
+// leafNumbers 返回当前局面作为叶节点时的证明数与反证数。
+// isOr 表示当前局面是否轮到进攻方走棋。
+func (s *solver) leafNumbers(isOr bool) (int, int) {
+	board := s.e.Board
+	if !board.IsGameOver() {
+		return 1, 1
+	}
+	isMaxPlayer := s.attacker
+	if !isOr {
+		isMaxPlayer = !s.attacker
+	}
+	return resultNumbers(isOr, s.e.terminalResult(board, isMaxPlayer))
+}
+
+// noMovesNumbers 返回未结束却没有合法走法的当前局面的证明数与反证数，isMaxPlayer 为行棋方。
+// NoMovesEvaluate 按 EvaluateFunc 的符号判断胜负，NoMovesLoss 判行棋方负，NoMovesDraw 视为进攻方未能取胜；
+// 其余规则记录 ErrNoLegalMoves，同样视为进攻方未能取胜。
+func (s *solver) noMovesNumbers(isOr, isMaxPlayer bool) (int, int) {
+	result := Draw
+	switch s.e.EvalOptions.NoMoves {
+	case NoMovesEvaluate:
+		s.e.stats.LeafEvals++
+		result = s.e.evaluatedResult(s.e.Board, isMaxPlayer)
+	case NoMovesLoss:
+		result = Loss
+	case NoMovesDraw:
+	default:
+		s.e.fail(ErrNoLegalMoves)
+	}
+	return resultNumbers(isOr, result)
+}
+
+// resultNumbers 将以行棋方视角的结果转换为证明数与反证数：进攻方获胜时已证明，其余结果已反证。
+func resultNumbers(isOr bool, result GameResult) (int, int) {
+	if (isOr && result == Win) || (!isOr && result == Loss) {
+		return 0, pnInfinity
+	}
+	return pnInfinity, 0
+}
+
+// exhausted 检查是否已达到节点数或时间限制。
+func (s *solver) exhausted() bool {
+	return s.nodes >= s.maxNodes || s.e.tm.softExpired()
+}
+
+// result 根据根节点的证明数与反证数生成搜索结果。
+func (s *solver) result(pn, dn int, move Move) SolveResult {
+	switch {
+	case pn == 0:
+		return SolveResult{Result: Win, Move: move, Nodes: s.nodes}
+	case dn == 0:
+		return SolveResult{Result: Loss, Nodes: s.nodes}
+	default:
+		return SolveResult{Result: Unknown, Nodes: s.nodes}
+	}
+}
+
+// pns 执行最佳优先的证明数搜索：反复从根节点沿最有证明价值的路径到达叶节点，展开后自底向上更新证明数。
+func (s *solver) pns() SolveResult {
+	root := &pnNode{isOr: true, isMaxPlayer: s.attacker}
+	root.pn, root.dn = s.leafNumbers(true)
+	s.e.stats.TreeSize++
+
+	for root.pn != 0 && root.dn != 0 && !s.exhausted() {
+		var path []Move
+		node := root
+		for len(node.children) > 0 {
+			node = node.mostProving()
+			s.e.Board.Move(node.move)
+			path = append(path, node.move)
+		}
+		s.e.reachDepth(len(path))
+		s.expand(node)
+		for n := node; n != nil; n = n.parent {
+			n.update()
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			s.e.Board.UndoMove(path[i])
+		}
+	}
+
+	var move Move
+	for _, child := range root.children {
+		if child.pn == 0 {
+			move = child.move
+			break
+		}
+	}
+	return s.result(root.pn, root.dn, move)
+}
+
+// expand 为叶节点生成全部子节点并计算它们的初始证明数与反证数，没有合法走法时按 NoMoves 规则直接得出结果。
+func (s *solver) expand(node *pnNode) {
+	s.nodes++
+	moves := s.e.legalMoves(s.e.Board, node.isMaxPlayer)
+	if len(moves) == 0 {
+		node.pn, node.dn = s.noMovesNumbers(node.isOr, node.isMaxPlayer)
+		return
+	}
+	for _, move := range moves {
+		child := &pnNode{move: move, parent: node, isOr: !node.isOr, isMaxPlayer: !node.isMaxPlayer}
+		s.e.Board.Move(move)
+		child.pn, child.dn = s.leafNumbers(child.isOr)
+		s.e.Board.UndoMove(move)
+		node.children = append(node.children, child)
+	}
+	s.e.stats.TreeSize += int64(len(node.children))
+}
+
+// update 根据子节点重新计算节点的证明数与反证数。
+// OR 节点：pn 为子节点 pn 的最小值，dn 为子节点 dn 之和；AND 节点相反。没有子节点时保留 expand 得出的结果。
+func (n *pnNode) update() {
+	if len(n.children) == 0 {
+		return
+	}
+	minimum, sum := pnInfinity, 0
+	for _, child := range n.children {
+		if n.isOr {
+			minimum = min(minimum, child.pn)
+			sum = addNumbers(sum, child.dn)
+		} else {
+			minimum = min(minimum, child.dn)
+			sum = addNumbers(sum, child.pn)
+		}
+	}
+	if n.isOr {
+		n.pn, n.dn = minimum, sum
+	} else {
+		n.pn, n.dn = sum, minimum
+	}
+}
+
+// mostProving 返回 OR 节点中证明数最小或 AND 节点中反证数最小的子节点。
+func (n *pnNode) mostProving() *pnNode {
+	best := n.children[0]
+	for _, child := range n.children[1:] {
+		if (n.isOr && child.pn < best.pn) || (!n.isOr && child.dn < best.dn) {
+			best = child
+		}
+	}
+	return best
+}
+
+// addNumbers 对证明数做饱和加法，结果不超过 pnInfinity。
+func addNumbers(a, b int) int {
+	if a >= pnInfinity-b {
+		return pnInfinity
+	}
+	return a + b
+}
+
+// dfpn 执行深度优先证明数搜索（df-pn），以置换表保存已搜索局面的证明数与反证数，
+// 在阈值内深度优先地展开最有证明价值的子节点，内存占用只取决于置换表大小。
+func (s *solver) dfpn() SolveResult {
+	s.table = make(map[uint64]pnEntry)
+	s.path = make(map[uint64]bool)
+	pn, dn := s.mid(pnInfinity-1, pnInfinity-1, true, s.attacker)
+
+	var move Move
+	if pn == 0 {
+		for _, m := range s.e.legalMoves(s.e.Board, s.attacker) {
+			s.e.Board.Move(m)
+			childPn, _ := s.lookup(false)
+			s.e.Board.UndoMove(m)
+			if childPn == 0 {
+				move = m
+				break
+			}
+		}
+	}
+	return s.result(pn, dn, move)
+}
+
+// mid 在阈值 (thpn, thdn) 内搜索当前局面，返回更新后的证明数与反证数。
+func (s *solver) mid(thpn, thdn int, isOr, isMaxPlayer bool) (int, int) {
+	board := s.e.Board
+	if board.IsGameOver() {
+		pn, dn := s.leafNumbers(isOr)
+		s.store(isOr, pn, dn)
+		return pn, dn
+	}
+
+	key := tableKey(board.Hash(), isOr)
+	if s.path[key] {
+		return pnInfinity, 0
+	}
+	s.path[key] = true
+	defer delete(s.path, key)
+
+	s.nodes++
+	s.e.reachDepth(s.depth)
+	moves := s.e.legalMoves(board, isMaxPlayer)
+	if len(moves) == 0 {
+		pn, dn := s.noMovesNumbers(isOr, isMaxPlayer)
+		s.store(isOr, pn, dn)
+		return pn, dn
+	}
+	for {
+		pn, dn, best, secondBest := s.childNumbers(moves, isOr)
+		if pn >= thpn || dn >= thdn || s.exhausted() {
+			s.store(isOr, pn, dn)
+			return pn, dn
+		}
+
+		board.Move(moves[best.index])
+		s.depth++
+		childPn, childDn := best.pn, best.dn
+		if isOr {
+			s.mid(min(thpn, addNumbers(secondBest, 1)), thdn-dn+childDn, !isOr, !isMaxPlayer)
+		} else {
+			s.mid(thpn-pn+childPn, min(thdn, addNumbers(secondBest, 1)), !isOr, !isMaxPlayer)
+		}
+		s.depth--
+		board.UndoMove(moves[best.index])
+	}
+}
+
+// dfpnChild 记录 DFPN 中一个子节点的下标与证明数、反证数。
+type dfpnChild struct {
+	index  int
+	pn, dn int
+}
+
+// childNumbers 汇总全部子节点的证明数与反证数，返回当前节点的 pn、dn、最有证明价值的子节点，
+// 以及次优子节点的 pn（OR 节点）或 dn（AND 节点），用于计算子节点阈值。
+func (s *solver) childNumbers(moves []Move, isOr bool) (int, int, dfpnChild, int) {
+	board := s.e.Board
+	minimum, second, sum := pnInfinity, pnInfinity, 0
+	var best dfpnChild
+	for i, move := range moves {
+		board.Move(move)
+		pn, dn := s.lookup(!isOr)
+		board.UndoMove(move)
+
+		value, other := dn, pn
+		if isOr {
+			value, other = pn, dn
+		}
+		sum = addNumbers(sum, other)
+		if value < minimum {
+			second = minimum
+			minimum = value
+			best = dfpnChild{index: i, pn: pn, dn: dn}
+		} else if value < second {
+			second = value
+		}
+	}
+	if isOr {
+		return minimum, sum, best, second
+	}
+	return sum, minimum, best, second
+}
+
+// lookup 返回当前局面在置换表中的证明数与反证数，未搜索过的局面按叶节点计算。
+// 当前路径上重复出现的局面视为进攻方未能取胜，避免在循环中无限搜索。
+func (s *solver) lookup(isOr bool) (int, int) {
+	key := tableKey(s.e.Board.Hash(), isOr)
+	if s.path[key] {
+		return pnInfinity, 0
+	}
+	if entry, ok := s.table[key]; ok {
+		s.e.stats.TTHits++
+		return entry.pn, entry.dn
+	}
+	return s.leafNumbers(isOr)
+}
+
+// store 将当前局面的证明数与反证数写入置换表。
+func (s *solver) store(isOr bool, pn, dn int) {
+	s.table[tableKey(s.e.Board.Hash(), isOr)] = pnEntry{pn: pn, dn: dn}
+}
+
+// tableKey 将局面哈希与行棋方组合为置换表的键。
+func tableKey(hash uint64, isOr bool) uint64 {
+	if isOr {
+		return hash
+	}
+	return hash ^ 0x9E3779B97F4A7C15
+}
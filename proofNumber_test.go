@@ -0,0 +1,126 @@
+package gotack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// nimMove 是 nimBoard 上一次取走的石子数。
+type nimMove int
+
+func (m nimMove) String() string { return strconv.Itoa(int(m)) }
+
+// nimBoard 是一堆石子的 Nim：双方轮流取走 1 到 3 颗，取走最后一颗的一方获胜。
+// 石子数为 4 的倍数时行棋方必败，否则取走 pile%4 颗即可获胜。
+type nimBoard struct {
+	pile int
+}
+
+func (b *nimBoard) Print() { fmt.Println(b.pile) }
+
+func (b *nimBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	var moves []Move
+	for n := 1; n <= min(3, b.pile); n++ {
+		moves = append(moves, nimMove(n))
+	}
+	return moves
+}
+
+func (b *nimBoard) Move(move Move) { b.pile -= int(move.(nimMove)) }
+
+func (b *nimBoard) UndoMove(move Move) { b.pile += int(move.(nimMove)) }
+
+func (b *nimBoard) IsGameOver() bool { return b.pile == 0 }
+
+func (b *nimBoard) EvaluateFunc(opts EvalOptions) float64 { return 0 }
+
+func (b *nimBoard) Hash() uint64 { return uint64(b.pile) }
+
+func (b *nimBoard) Clone() Board { return &nimBoard{pile: b.pile} }
+
+// Result 返回终局时行棋方的结果：对手取走了最后一颗石子，行棋方失败。
+func (b *nimBoard) Result() GameResult { return Loss }
+
+func TestProofNumberSolvesNim(t *testing.T) {
+	for _, tt := range []GameTreeType{ProofNumber, DFPN} {
+		for pile := 1; pile <= 12; pile++ {
+			for _, isMaxPlayer := range []bool{true, false} {
+				e := NewEvaluator(tt, NewEvaluatorOptions(WithBoard(&nimBoard{pile: pile}), WithIsMaxPlayer(isMaxPlayer)))
+				result := e.Solve()
+				want, wantMove := Loss, Move(nil)
+				if pile%4 != 0 {
+					want, wantMove = Win, nimMove(pile%4)
+				}
+				if result.Result != want || result.Move != wantMove {
+					t.Errorf("%d/pile=%d/max=%v: %v with move %v, want %v with move %v",
+						tt, pile, isMaxPlayer, result.Result, result.Move, want, wantMove)
+				}
+			}
+		}
+	}
+}
+
+// passGame 是一个只有一条路线的小游戏：根局面的进攻方没有合法走法，只能停一手；
+// 随后防守方唯一的走法让进攻方可以一步获胜。
+type passGame struct {
+	history []string
+}
+
+// passGameMoves 是每个历史之后行棋方的合法走法。
+var passGameMoves = map[string][]string{
+	"":             nil,
+	"pass":         {"blunder"},
+	"pass blunder": {"win"},
+}
+
+func (g *passGame) key() string { return strings.Join(g.history, " ") }
+
+func (g *passGame) Print() { fmt.Println(g.key()) }
+
+func (g *passGame) GetAllMoves(isMaxPlayer bool) []Move {
+	var moves []Move
+	for _, m := range passGameMoves[g.key()] {
+		moves = append(moves, betMove(m))
+	}
+	return moves
+}
+
+func (g *passGame) Move(move Move) { g.history = append(g.history, move.String()) }
+
+func (g *passGame) UndoMove(move Move) { g.history = g.history[:len(g.history)-1] }
+
+func (g *passGame) IsGameOver() bool { return len(g.history) == 3 }
+
+func (g *passGame) EvaluateFunc(opts EvalOptions) float64 { return 0 }
+
+func (g *passGame) Hash() uint64 { return uint64(len(g.history)) }
+
+func (g *passGame) Clone() Board { return &passGame{history: append([]string(nil), g.history...)} }
+
+func (g *passGame) PassMove(isMaxPlayer bool) Move { return betMove("pass") }
+
+// Result 返回终局时行棋方（防守方）的结果。
+func (g *passGame) Result() GameResult { return Loss }
+
+func TestProofNumberAppliesNoMovesRule(t *testing.T) {
+	tests := []struct {
+		rule     NoMovesRule
+		want     GameResult
+		wantMove Move
+	}{
+		{NoMovesPass, Win, betMove("pass")},
+		{NoMovesLoss, Loss, nil},
+		{NoMovesDraw, Loss, nil},
+	}
+	for _, tt := range []GameTreeType{ProofNumber, DFPN} {
+		for _, tc := range tests {
+			e := NewEvaluator(tt, NewEvaluatorOptions(WithBoard(&passGame{}), WithNoMovesRule(tc.rule)))
+			result := e.Solve()
+			if result.Result != tc.want || result.Move != tc.wantMove {
+				t.Errorf("%d/rule=%d: %v with move %v, want %v with move %v", tt, tc.rule, result.Result, result.Move, tc.want, tc.wantMove)
+			}
+		}
+	}
+}
@@ -3,19 +3,42 @@ package gotack
 import "math"
 
 func (e *Evaluator) pvs(depth int, alpha, beta float64, isMaximizingPlayer bool, opts *EvalOptions) (float64, []Move) {
+	if e.stopping() {
+		return 0, nil
+	}
+	e.nodes++
+	ply := e.Depth - depth
+	e.reachDepth(ply)
+	e.pvTable[ply] = e.pvTable[ply][:0]
+	if e.repeated(depth) {
+		return e.drawScore(), nil
+	}
+	defer e.leavePosition(depth)
+
+	if value, ok := e.probeTablebase(e.Board, isMaximizingPlayer, ply); ok {
+		return value, nil
+	}
+
 	if depth == 0 || e.Board.IsGameOver() {
-		opts.Extra["depth"] = e.Depth - depth
-		return e.Board.EvaluateFunc(*opts), nil
+		opts.Extra["depth"] = ply
+		return e.evaluateLeaf(isMaximizingPlayer, ply, opts), nil
+	}
+
+	moves := e.legalMoves(e.Board, isMaximizingPlayer)
+	if len(moves) == 0 {
+		return e.noMovesValue(isMaximizingPlayer, ply), nil
+	}
+	if ply == 0 {
+		moves = e.rootMoves(moves)
 	}
 
 	var bestMoves []Move
 	var eval float64
 	firstMove := true
 
-	moves := e.Board.GetAllMoves(isMaximizingPlayer)
 	if isMaximizingPlayer {
 		maxEval := math.Inf(-1)
-		for _, move := range moves {
+		for i, move := range moves {
 			e.Board.Move(move)
 			if firstMove {
 				eval, _ = e.pvs(depth-1, alpha, beta, false, opts)
@@ -25,19 +48,25 @@ func (e *Evaluator) pvs(depth int, alpha, beta float64, isMaximizingPlayer bool,
 				eval, _ = e.pvs(depth-1, alpha, alpha+1, false, opts)
 				// If the result is promising but not proven, re-search
 				if eval > alpha && eval < beta {
+					e.stats.ReSearches++
 					eval, _ = e.pvs(depth-1, alpha, beta, false, opts)
 				}
 			}
 			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
 
 			if eval > maxEval {
 				maxEval = eval
 				bestMoves = []Move{move}
+				e.updatePV(ply, move)
 			} else if eval == maxEval {
 				bestMoves = append(bestMoves, move)
 			}
 			alpha = math.Max(alpha, eval)
 			if beta <= alpha {
+				e.cutoff(i)
 				break
 			}
 		}
@@ -47,7 +76,7 @@ func (e *Evaluator) pvs(depth int, alpha, beta float64, isMaximizingPlayer bool,
 		return maxEval, bestMoves
 	} else {
 		minEval := math.Inf(1)
-		for _, move := range moves {
+		for i, move := range moves {
 			e.Board.Move(move)
 			if firstMove {
 				eval, _ = e.pvs(depth-1, alpha, beta, true, opts)
@@ -55,19 +84,25 @@ func (e *Evaluator) pvs(depth int, alpha, beta float64, isMaximizingPlayer bool,
 			} else {
 				eval, _ = e.pvs(depth-1, beta-1, beta, true, opts)
 				if eval < beta && eval > alpha {
+					e.stats.ReSearches++
 					eval, _ = e.pvs(depth-1, alpha, beta, true, opts)
 				}
 			}
 			e.Board.UndoMove(move)
+			if e.stopped {
+				break
+			}
 
 			if eval < minEval {
 				minEval = eval
 				bestMoves = []Move{move}
+				e.updatePV(ply, move)
 			} else if eval == minEval {
 				bestMoves = append(bestMoves, move)
 			}
 			beta = math.Min(beta, eval)
 			if alpha >= beta {
+				e.cutoff(i)
 				break
 			}
 		}
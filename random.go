@@ -0,0 +1,53 @@
+package gotack
+
+import (
+	"maps"
+	"math/rand"
+)
+
+// newRand 根据评估选项创建本次搜索使用的随机数生成器。
+// 若设置了 RandSource 则直接使用该随机源，否则使用 Seed 创建新的随机源，
+// 因此相同的 Seed 总能得到相同的搜索结果。
+func (opts *EvalOptions) newRand() *rand.Rand {
+	if opts.RandSource != nil {
+		return rand.New(opts.RandSource)
+	}
+	return rand.New(rand.NewSource(opts.Seed))
+}
+
+// deriveRands 从评估器的主随机流中依次派生 n 个相互独立的随机流，供并行的 goroutine 各自使用。
+// 派生顺序是确定的，所以在种子相同时每个 goroutine 得到的随机流也相同。
+func (e *Evaluator) deriveRands(n int) []*rand.Rand {
+	rngs := make([]*rand.Rand, n)
+	for i := range rngs {
+		rngs[i] = rand.New(rand.NewSource(e.rng.Int63()))
+	}
+	return rngs
+}
+
+// fork 创建一个用于并行搜索的评估器副本，副本拥有独立的棋盘克隆、随机流和时间管理器，
+// 搜索过程中会修改的路径哈希、对局历史、主要变例与 Multi-PV 状态也都复制一份，副本之间互不影响。
+func (e *Evaluator) fork(rng *rand.Rand) *Evaluator {
+	worker := *e
+	worker.Board = e.Board.Clone()
+	worker.rng = rng
+	tm := *e.tm
+	worker.tm = &tm
+	worker.onInfo = nil
+	worker.nodes = 0
+	worker.stats = SearchStats{}
+
+	worker.pathHashes = append([]uint64(nil), e.pathHashes...)
+	worker.historyCounts = maps.Clone(e.historyCounts)
+	worker.treePath = maps.Clone(e.treePath)
+	worker.excluded = maps.Clone(e.excluded)
+	worker.lines = append([]PVLine(nil), e.lines...)
+	worker.pv = append([]Move(nil), e.pv...)
+	if e.pvTable != nil {
+		worker.pvTable = make([][]Move, len(e.pvTable))
+		for i, line := range e.pvTable {
+			worker.pvTable[i] = append([]Move(nil), line...)
+		}
+	}
+	return &worker
+}
@@ -0,0 +1,111 @@
+package gotack
+
+// repetitionEnabled 检查是否启用了重复局面检测。
+func (e *Evaluator) repetitionEnabled() bool {
+	return e.EvalOptions.SearchRepetitions > 0 || e.EvalOptions.GameRepetitions > 0
+}
+
+// initRepetition 根据调用者提供的对局历史初始化重复检测，并将根局面加入搜索路径。
+func (e *Evaluator) initRepetition() {
+	e.pathHashes = e.pathHashes[:0]
+	e.historyCounts = make(map[uint64]int)
+	if !e.repetitionEnabled() {
+		return
+	}
+	for _, hash := range e.EvalOptions.History {
+		e.historyCounts[hash]++
+	}
+	e.pathHashes = append(e.pathHashes, e.Board.Hash())
+}
+
+// isRepetition 检查哈希为 hash 的局面再次出现时是否按规则判和：
+// 在搜索路径（含根局面）中累计出现 SearchRepetitions 次，或连同对局历史累计出现 GameRepetitions 次。
+func (e *Evaluator) isRepetition(hash uint64, pathCount int) bool {
+	opts := e.EvalOptions
+	if opts.SearchRepetitions > 0 && pathCount+1 >= opts.SearchRepetitions {
+		return true
+	}
+	return opts.GameRepetitions > 0 && pathCount+e.historyCounts[hash]+1 >= opts.GameRepetitions
+}
+
+// repeated 在搜索进入非根节点时检查当前局面是否构成重复。
+// 构成重复时返回 true，否则将当前局面压入搜索路径，调用者需要在离开节点时调用 leavePosition。
+func (e *Evaluator) repeated(depth int) bool {
+	if !e.repetitionEnabled() || depth >= e.Depth {
+		return false
+	}
+	hash := e.Board.Hash()
+	count := 0
+	for _, h := range e.pathHashes {
+		if h == hash {
+			count++
+		}
+	}
+	if e.isRepetition(hash, count) {
+		return true
+	}
+	e.pathHashes = append(e.pathHashes, hash)
+	return false
+}
+
+// leavePosition 在离开节点时将其从搜索路径中弹出，与 repeated 配对使用。
+func (e *Evaluator) leavePosition(depth int) {
+	if !e.repetitionEnabled() || depth >= e.Depth {
+		return
+	}
+	e.pathHashes = e.pathHashes[:len(e.pathHashes)-1]
+}
+
+// drawScore 返回以最大化玩家视角的和棋评估值。
+// Contempt 为正时根节点行棋方认为和棋略差于均势，从而倾向于避免重复。
+func (e *Evaluator) drawScore() float64 {
+	if e.EvalOptions.IsMaxPlayer {
+		return 0 - e.EvalOptions.Contempt
+	}
+	return e.EvalOptions.Contempt
+}
+
+// treeRepetition 检查 UCT 是否需要做重复局面检测，多人棋盘不做检测。
+func (e *Evaluator) treeRepetition() bool {
+	return e.repetitionEnabled() && !e.multiPlayer
+}
+
+// resetTreePath 在每次迭代开始选择时清空 treePath，并加入 node 的全部祖先节点。
+func (e *Evaluator) resetTreePath(node *Node) {
+	if !e.treeRepetition() {
+		return
+	}
+	if e.treePath == nil {
+		e.treePath = make(map[uint64]int)
+	}
+	clear(e.treePath)
+	for n := node.Parent; n != nil; n = n.Parent {
+		e.treePath[n.hash()]++
+	}
+}
+
+// enterTreeNode 在选择从 node 下降到其子节点前将 node 加入 treePath。
+func (e *Evaluator) enterTreeNode(node *Node) {
+	if e.treeRepetition() {
+		e.treePath[node.hash()]++
+	}
+}
+
+// nodeRepeated 检查 UCT 节点的局面是否与其祖先节点或对局历史构成重复。
+// 祖先节点的出现次数来自 treePath，因此只能用于本次迭代 selectNode 选择路径上的节点。
+func (e *Evaluator) nodeRepeated(node *Node) bool {
+	if !e.treeRepetition() || node.Parent == nil {
+		return false
+	}
+	hash := node.hash()
+	return e.isRepetition(hash, e.treePath[hash])
+}
+
+// hash 返回节点局面的哈希值，首次计算后缓存。
+func (n *Node) hash() uint64 {
+	if !n.hashReady {
+		n.stateHash = n.State.Hash()
+		n.hashReady = true
+	}
+	return n.stateHash
+}
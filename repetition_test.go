@@ -0,0 +1,101 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+// shuffleBoard 是一个可以无限循环的游戏：最大化玩家可以 settle 以 -1 结束游戏，或者 shuffle 到另一个局面，
+// 最小化玩家在那里只能 shuffle 回来。不检测重复时循环的局面评估为 0.5，检测重复时循环判为和棋。
+type shuffleBoard struct {
+	pos     int
+	settled bool
+}
+
+func (b *shuffleBoard) Print() { fmt.Println(b.pos, b.settled) }
+
+func (b *shuffleBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	switch {
+	case b.settled:
+		return nil
+	case b.pos == 0:
+		return []Move{betMove("shuffle"), betMove("settle")}
+	}
+	return []Move{betMove("shuffle")}
+}
+
+func (b *shuffleBoard) Move(move Move) {
+	if move == betMove("settle") {
+		b.settled = true
+		return
+	}
+	b.pos = 1 - b.pos
+}
+
+func (b *shuffleBoard) UndoMove(move Move) {
+	if move == betMove("settle") {
+		b.settled = false
+		return
+	}
+	b.pos = 1 - b.pos
+}
+
+func (b *shuffleBoard) IsGameOver() bool { return b.settled }
+
+func (b *shuffleBoard) EvaluateFunc(opts EvalOptions) float64 {
+	if b.settled {
+		return -1
+	}
+	return 0.5
+}
+
+func (b *shuffleBoard) Hash() uint64 {
+	if b.settled {
+		return 2
+	}
+	return uint64(b.pos)
+}
+
+func (b *shuffleBoard) Clone() Board {
+	clone := *b
+	return &clone
+}
+
+func TestRepetitionDrawWithContempt(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []EvalOption
+		want  betMove
+		value float64
+	}{
+		{"no detection", nil, "shuffle", 0.5},
+		{"draw", []EvalOption{WithRepetition(2, 0)}, "shuffle", 0},
+		{"small contempt", []EvalOption{WithRepetition(2, 0), WithContempt(0.5)}, "shuffle", -0.5},
+		{"large contempt", []EvalOption{WithRepetition(2, 0), WithContempt(2)}, "settle", -1},
+		// 对局历史中已经出现过一次对方的局面，第二次出现即按对局规则判和。
+		{"game history", []EvalOption{WithRepetition(0, 2), WithHistory([]uint64{1}), WithContempt(2)}, "settle", -1},
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS, UCT} {
+		for _, tc := range tests {
+			t.Run(fmt.Sprintf("%d/%s", tt, tc.name), func(t *testing.T) {
+				opts := append([]EvalOption{
+					WithBoard(&shuffleBoard{}),
+					WithDepth(4),
+					WithIterations(2000),
+					WithTimeLimit(0),
+					WithSeed(1),
+				}, tc.opts...)
+				result, err := NewEvaluator(tt, NewEvaluatorOptions(opts...)).Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.BestMoves) == 0 || result.BestMoves[0] != tc.want {
+					t.Fatalf("best moves %v, want %v", result.BestMoves, tc.want)
+				}
+				if tt != UCT && result.Value != tc.value {
+					t.Fatalf("value %v, want %v", result.Value, tc.value)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package gotack
+
+import "math"
+
+// MateScore 是终局胜负对应的评估值基数。距离根节点 ply 步的胜局评估为 MateScore-ply，
+// 负局为 -(MateScore-ply)，因此搜索会优先选择更快的胜利和更慢的失败。
+// EvaluateFunc 的返回值应远小于 MateScore。
+const MateScore = 1e9
+
+// maxMatePly 是能够从评估值中识别出胜负步数的最大距离。
+const maxMatePly = 10000
+
+// SearchResult 表示一次搜索的结果。
+type SearchResult struct {
+	// Value 为根节点以最大化玩家视角的评估值。
+	Value float64
+	// BestMoves 为评估值相同的全部最佳走法。
+	BestMoves []Move
+	// MateIn 表示根节点行棋方已知的胜负距离（以步数计）：大于 0 表示 MateIn 步后获胜，
+	// 小于 0 表示 -MateIn 步后失败，0 表示搜索未发现确定的胜负。MCTS 类算法只对模拟结果取平均而不证明胜负，MateIn 总为 0。
+	MateIn int
+	// PV 为主要变例，即从根局面出发双方的最佳走法序列。AlphaBeta/PVS 来自搜索树中的主要变例，
+	// MCTS 类算法沿访问次数最多的子节点得到，其余算法只包含最佳走法。
+	PV []Move
+	// FromBook 表示最佳走法是否直接来自开局库，此时 Value 为 0。
+	FromBook bool
+	// Lines 为 MultiPV 大于 1 时根节点最好的若干个走法，按从好到坏排列，第一项与 BestMoves、PV 对应。
+	// AlphaBeta/PVS 逐个排除已找到的走法重新搜索，UCT 取访问次数最多的子节点，其余算法为 nil。
+	Lines []PVLine
+	// MixedStrategy 为 SimultaneousUCT 搜索得到的根节点行棋方的混合策略，与 Evaluator.MixedStrategy 相同，其余算法为 nil。
+	MixedStrategy []MoveProbability
+	// Stats 为本次搜索的统计信息，结果来自开局库时为零值。
+	Stats SearchStats
+}
+
+// newSearchResult 根据评估值、最佳走法与本次搜索的主要变例生成搜索结果，并从评估值中识别胜负距离。
+func (e *Evaluator) newSearchResult(value float64, bestMoves []Move) *SearchResult {
+	pv := e.pv
+	if len(pv) == 0 && len(bestMoves) > 0 {
+		pv = []Move{bestMoves[0]}
+	}
+	return &SearchResult{
+		Value:         value,
+		BestMoves:     bestMoves,
+		MateIn:        mateIn(value, e.EvalOptions.IsMaxPlayer),
+		PV:            pv,
+		Lines:         e.resultLines(),
+		MixedStrategy: e.MixedStrategy,
+		Stats:         e.searchStats(),
+	}
+}
+
+// resultLines 返回搜索结果中的 Multi-PV 结果，最多 MultiPV 项。
+func (e *Evaluator) resultLines() []PVLine {
+	if e.EvalOptions.MultiPV <= 1 {
+		return nil
+	}
+	return e.lines[:min(len(e.lines), e.EvalOptions.MultiPV)]
+}
+
+// mateIn 将以最大化玩家视角的评估值转换为根节点行棋方的胜负距离，不是胜负评估值时返回 0。
+func mateIn(value float64, isMaxPlayer bool) int {
+	if !isMaxPlayer {
+		value = -value
+	}
+	plies := MateScore - math.Abs(value)
+	if plies < 0 || plies > maxMatePly {
+		return 0
+	}
+	if value > 0 {
+		return int(plies)
+	}
+	return -int(plies)
+}
+
+// evaluateLeaf 返回叶节点以最大化玩家视角的评估值。
+// 若局面已结束且棋盘实现了 ResultBoard，则按距离根节点的步数 ply 转换为胜负评估值，和棋为 0；
+// 否则调用 EvaluateFunc。
+func (e *Evaluator) evaluateLeaf(isMaxPlayer bool, ply int, opts *EvalOptions) float64 {
+	e.stats.LeafEvals++
+	if rb, ok := e.Board.(ResultBoard); ok && e.Board.IsGameOver() {
+		return resultValue(rb.Result(), isMaxPlayer, ply)
+	}
+	return e.Board.EvaluateFunc(*opts)
+}
+
+// resultValue 将以行棋方视角的胜负结果转换为以最大化玩家视角的评估值，ply 为距离根节点的步数，和棋与未知结果为 0。
+func resultValue(result GameResult, isMaxPlayer bool, ply int) float64 {
+	value := 0.0
+	switch result {
+	case Win:
+		value = MateScore - float64(ply)
+	case Loss:
+		value = -(MateScore - float64(ply))
+	}
+	if !isMaxPlayer {
+		value = 0 - value // 避免和棋得到 -0
+	}
+	return value
+}
+
+// updatePV 以 move 开头、接上子节点的主要变例，更新第 ply 层的主要变例。
+func (e *Evaluator) updatePV(ply int, move Move) {
+	e.pvTable[ply] = append(append(e.pvTable[ply][:0], move), e.pvTable[ply+1]...)
+}
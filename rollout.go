@@ -0,0 +1,119 @@
+package gotack
+
+import (
+	"math"
+	"math/rand"
+)
+
+// RolloutPolicy 定义了 UCT 模拟（rollout）阶段的走法选择策略。
+// 模拟从新扩展的节点出发，最多进行 RolloutDepth 步，每一步都由策略从当前合法走法中挑选，
+// 不同的游戏可以按需选择或自行实现合适的策略。
+type RolloutPolicy interface {
+	// SelectMove 为模拟中的当前玩家选择下一步走法。
+	// 参数:
+	//   - state Board: 模拟中的棋盘状态，策略可以在其上 Move/UndoMove 试探，但返回前必须复原。
+	//   - moves []Move: 当前玩家的全部合法走法，至少包含一个元素。
+	//   - isMaxPlayer bool: 当前行棋方是否为最大化玩家。
+	//   - opts *EvalOptions: 评估选项，供需要调用 EvaluateFunc 的策略使用。
+	//   - rng *rand.Rand: 本次搜索的随机数生成器，策略中的所有随机选择都应使用它以保证结果可复现。
+	// 返回值:
+	//   - Move: 选中的走法；返回 nil 表示立即结束模拟并评估当前局面。
+	SelectMove(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions, rng *rand.Rand) Move
+}
+
+// RandomRollout 在合法走法中均匀随机选择，是 UCT 的默认模拟策略。
+type RandomRollout struct{}
+
+// SelectMove 实现 RolloutPolicy 接口。
+func (RandomRollout) SelectMove(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions, rng *rand.Rand) Move {
+	return moves[rng.Intn(len(moves))]
+}
+
+// EpsilonGreedyRollout 以 1-Epsilon 的概率选择 EvaluateFunc 评估最好的走法，
+// 以 Epsilon 的概率均匀随机选择。
+type EpsilonGreedyRollout struct {
+	// Epsilon 为随机探索的概率，取值范围 [0, 1]。
+	Epsilon float64
+}
+
+// SelectMove 实现 RolloutPolicy 接口。
+func (p EpsilonGreedyRollout) SelectMove(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions, rng *rand.Rand) Move {
+	if rng.Float64() < p.Epsilon {
+		return moves[rng.Intn(len(moves))]
+	}
+	scores := scoreMoves(state, moves, isMaxPlayer, opts)
+	best := 0
+	for i, score := range scores {
+		if score > scores[best] {
+			best = i
+		}
+	}
+	return moves[best]
+}
+
+// SoftmaxRollout 按启发式分数的 softmax 分布随机选择走法。
+// Temperature 越大越接近均匀随机，越小越接近贪心；小于等于 0 时退化为贪心选择。
+type SoftmaxRollout struct {
+	// Temperature 为 softmax 温度。
+	Temperature float64
+}
+
+// SelectMove 实现 RolloutPolicy 接口。
+func (p SoftmaxRollout) SelectMove(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions, rng *rand.Rand) Move {
+	scores := scoreMoves(state, moves, isMaxPlayer, opts)
+	return moves[sampleSoftmax(scores, p.Temperature, rng)]
+}
+
+// EvaluateRollout 不进行任何模拟，直接使用 EvaluateFunc 评估新扩展的节点。
+type EvaluateRollout struct{}
+
+// SelectMove 实现 RolloutPolicy 接口，始终返回 nil。
+func (EvaluateRollout) SelectMove(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions, rng *rand.Rand) Move {
+	return nil
+}
+
+// rolloutDepth 返回 MCTS 模拟最多走的步数，Extra["AheadStep"] 优先于 RolloutDepth。
+func (opts *EvalOptions) rolloutDepth() int {
+	return getOptionInt(opts.Extra, "AheadStep", opts.RolloutDepth)
+}
+
+// scoreMoves 在 state 上依次试走每一步并调用 EvaluateFunc，
+// 返回以当前行棋方视角衡量的分数（越大越好）。
+func scoreMoves(state Board, moves []Move, isMaxPlayer bool, opts *EvalOptions) []float64 {
+	scores := make([]float64, len(moves))
+	evaluateMoveRange(state, moves, scores, opts)
+	if !isMaxPlayer {
+		for i := range scores {
+			scores[i] = -scores[i]
+		}
+	}
+	return scores
+}
+
+// sampleSoftmax 按 softmax(scores / temperature) 的分布抽取一个下标。
+func sampleSoftmax(scores []float64, temperature float64, rng *rand.Rand) int {
+	best := 0
+	for i, score := range scores {
+		if score > scores[best] {
+			best = i
+		}
+	}
+	if temperature <= 0 {
+		return best
+	}
+
+	weights := make([]float64, len(scores))
+	total := 0.0
+	for i, score := range scores {
+		weights[i] = math.Exp((score - scores[best]) / temperature)
+		total += weights[i]
+	}
+	r := rng.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r < 0 {
+			return i
+		}
+	}
+	return best
+}
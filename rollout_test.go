@@ -0,0 +1,49 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+// rolloutPath 用 policy 从 board 出发模拟，返回模拟中走过的走法下标。
+func rolloutPath(policy RolloutPolicy, board *pickBoard, opts ...EvalOption) []pickMove {
+	e := NewEvaluator(UCT, NewEvaluatorOptions(append([]EvalOption{WithBoard(board), WithRolloutPolicy(policy)}, opts...)...))
+	e.rng = e.EvalOptions.newRand()
+	state, _ := e.rollout(&Node{State: board, IsMaxPlayer: true}, e.EvalOptions.rolloutDepth())
+	return state.(*pickBoard).path
+}
+
+func TestRolloutPoliciesProduceDifferentPlayouts(t *testing.T) {
+	board := newPickBoard(3, 0, 10, 5)
+
+	// 默认的模拟步数为 0，任何策略都不会走棋。
+	if path := rolloutPath(EpsilonGreedyRollout{}, board); len(path) != 0 {
+		t.Fatalf("rollout without a depth played %v", path)
+	}
+	if path := rolloutPath(EvaluateRollout{}, board, WithRolloutDepth(3)); len(path) != 0 {
+		t.Fatalf("EvaluateRollout played %v", path)
+	}
+
+	// 贪心策略总是为行棋方选择最好的走法：最大化玩家选 1，最小化玩家选 0。
+	greedy := []pickMove{1, 0, 1}
+	for _, opts := range [][]EvalOption{
+		{WithRolloutDepth(3)},
+		{WithExtra("AheadStep", 3)},
+	} {
+		if path := rolloutPath(EpsilonGreedyRollout{}, board, opts...); fmt.Sprint(path) != fmt.Sprint(greedy) {
+			t.Fatalf("greedy rollout %v, want %v", path, greedy)
+		}
+	}
+	if path := rolloutPath(EpsilonGreedyRollout{}, board, WithRolloutDepth(2)); fmt.Sprint(path) != fmt.Sprint(greedy[:2]) {
+		t.Fatalf("greedy rollout with depth 2 %v, want %v", path, greedy[:2])
+	}
+
+	// 随机策略在不同种子下走出不同的路线。
+	paths := make(map[string]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		paths[fmt.Sprint(rolloutPath(RandomRollout{}, board, WithRolloutDepth(3), WithSeed(seed)))] = true
+	}
+	if len(paths) < 2 {
+		t.Fatalf("random rollouts %v, want several different playouts", paths)
+	}
+}
@@ -0,0 +1,329 @@
+package gotack
+
+import (
+	"math"
+	"time"
+)
+
+// SimultaneousSelectionType 表示同时行动博弈中每个玩家在节点上独立选择走法的方式。
+type SimultaneousSelectionType int
+
+const (
+	DecoupledUCT SimultaneousSelectionType = iota // 每个玩家按自己的 UCB1 统计独立选择（DUCT）
+	Exp3                                          // 每个玩家按 Exp3 对抗性老虎机算法随机选择
+)
+
+// MoveProbability 表示混合策略中一个走法及其被选择的概率。
+type MoveProbability struct {
+	Move        Move
+	Probability float64
+}
+
+// simNode 是同时行动博弈搜索树中的节点，两名玩家在节点上各自维护独立的走法统计。
+type simNode struct {
+	visits   int
+	maxMoves []Move
+	minMoves []Move
+	maxStats []simStats
+	minStats []simStats
+	children map[[2]int]*simNode
+}
+
+// simStats 记录一名玩家在节点上某个走法的统计信息。
+// 统计中保存的都是最大化玩家视角的原始评估值，选择时才按当前的评估值范围归一化为该玩家的奖励，
+// 因此评估值范围在搜索中扩大时，之前累计的统计不会与之后的混用不同的尺度。
+type simStats struct {
+	visits int     // 该走法被选择的次数
+	value  float64 // 累计的原始评估值
+	gain   float64 // Exp3 的累计重要性加权原始评估值 Σv/p
+	weight float64 // Exp3 的累计重要性权重 Σ1/p，用于将 gain 归一化
+	prob   float64 // Exp3 最近一次选择该走法时的概率
+}
+
+// simSearch 保存一次同时行动搜索的状态。
+type simSearch struct {
+	e         *Evaluator
+	board     Board
+	joint     SimultaneousBoard
+	selection SimultaneousSelectionType
+	gamma     float64
+	aheadStep int
+	low, high float64 // 观察到的评估值范围，用于将奖励归一化到 [0, 1]
+	depth     int     // 当前节点距离根节点的步数
+}
+
+// simultaneousUCT 对同时行动博弈执行解耦的 MCTS：每个节点上两名玩家按 SimultaneousSelection
+// 各自独立地选择走法，联合走法决定子节点，模拟结果分别以双方视角更新各自的统计。
+// 搜索结束后根据根节点上 IsMaxPlayer 一方的选择频率得到混合策略，保存在 Evaluator.MixedStrategy 与 SearchResult.MixedStrategy 中，
+// 并返回其中概率最大的走法。模拟阶段双方均匀随机选择走法。
+func (e *Evaluator) simultaneousUCT(opts *EvalOptions) (float64, []Move) {
+	joint, ok := e.Board.(SimultaneousBoard)
+	if !ok {
+		return 0.0, nil
+	}
+	search := &simSearch{
+		e:         e,
+		board:     e.Board.Clone(),
+		joint:     joint,
+		selection: opts.SimultaneousSelection,
+		gamma:     opts.Exp3Gamma,
+		aheadStep: opts.rolloutDepth(),
+		low:       math.Inf(1),
+		high:      math.Inf(-1),
+	}
+	if j, ok := search.board.(SimultaneousBoard); ok {
+		search.joint = j
+	}
+
+	iterations := opts.Iterations
+	if iterations == 0 {
+		iterations = math.MaxInt32
+	}
+
+	root := &simNode{}
+	e.stats.TreeSize++
+	var lastInfo time.Duration
+	for i := 0; i < iterations; i++ {
+		if i > 0 && (e.tm.softExpired() || e.nodesExhausted()) {
+			break
+		}
+		if i > 0 && i%timeCheckInterval == 0 && e.infoDue(&lastInfo) {
+			if _, value, bestMoves := search.best(root, opts.IsMaxPlayer); bestMoves != nil {
+				e.onInfo(e.searchInfo(1, value, bestMoves, nil))
+			}
+		}
+		e.nodes++
+		e.stats.Playouts++
+		search.iterate(root)
+	}
+
+	strategy, value, bestMoves := search.best(root, opts.IsMaxPlayer)
+	e.MixedStrategy = strategy
+	return value, bestMoves
+}
+
+// best 返回根节点上一方的混合策略、其中概率最大的走法及该走法以最大化玩家视角的平均评估值，根节点尚未展开时返回 nil。
+func (s *simSearch) best(root *simNode, isMaxPlayer bool) ([]MoveProbability, float64, []Move) {
+	strategy := s.strategy(root, isMaxPlayer)
+	if len(strategy) == 0 {
+		return nil, 0.0, nil
+	}
+	best := 0
+	for i, mp := range strategy {
+		if mp.Probability > strategy[best].Probability {
+			best = i
+		}
+	}
+	stats := root.minStats
+	if isMaxPlayer {
+		stats = root.maxStats
+	}
+	return strategy, stats[best].value / float64(max(1, stats[best].visits)), []Move{strategy[best].Move}
+}
+
+// iterate 从 node 出发执行一次选择、扩展、模拟与反向传播，返回以最大化玩家视角的评估值。
+func (s *simSearch) iterate(node *simNode) float64 {
+	if s.board.IsGameOver() {
+		s.e.stats.LeafEvals++
+		return s.observe(s.board.EvaluateFunc(*s.e.EvalOptions))
+	}
+	if node.children == nil {
+		s.e.stats.MoveGenerations += 2
+		node.maxMoves = s.board.GetAllMoves(true)
+		node.minMoves = s.board.GetAllMoves(false)
+		node.maxStats = make([]simStats, len(node.maxMoves))
+		node.minStats = make([]simStats, len(node.minMoves))
+		node.children = make(map[[2]int]*simNode)
+	}
+	if len(node.maxMoves) == 0 || len(node.minMoves) == 0 {
+		s.e.stats.LeafEvals++
+		return s.observe(s.board.EvaluateFunc(*s.e.EvalOptions))
+	}
+
+	i := s.choose(node.maxStats, node.visits, true)
+	j := s.choose(node.minStats, node.visits, false)
+	move := s.joint.JointMove(node.maxMoves[i], node.minMoves[j])
+
+	var value float64
+	s.board.Move(move)
+	s.depth++
+	s.e.reachDepth(s.depth)
+	child, ok := node.children[[2]int{i, j}]
+	if ok {
+		value = s.iterate(child)
+	} else {
+		node.children[[2]int{i, j}] = &simNode{}
+		s.e.stats.TreeSize++
+		value = s.simulate()
+	}
+	s.depth--
+	s.board.UndoMove(move)
+
+	node.visits++
+	s.update(&node.maxStats[i], value)
+	s.update(&node.minStats[j], value)
+	return value
+}
+
+// choose 按选择方式为一名玩家选出走法下标，isMaxPlayer 表示 stats 是否属于最大化玩家。
+func (s *simSearch) choose(stats []simStats, visits int, isMaxPlayer bool) int {
+	if s.selection == Exp3 {
+		probs := s.exp3Probabilities(stats, isMaxPlayer)
+		r := s.e.rng.Float64()
+		chosen := len(stats) - 1
+		for i, p := range probs {
+			r -= p
+			if r < 0 {
+				chosen = i
+				break
+			}
+		}
+		stats[chosen].prob = probs[chosen]
+		return chosen
+	}
+
+	// 两名玩家独立选择，值相同时随机打破平局，避免对称博弈中双方陷入相同的确定性循环
+	var ties []int
+	bestValue := math.Inf(-1)
+	for i, st := range stats {
+		value := math.Inf(1)
+		if st.visits > 0 {
+			value = s.reward(st.value/float64(st.visits), isMaxPlayer) + math.Sqrt(2*math.Log(float64(visits))/float64(st.visits))
+		}
+		if value > bestValue {
+			bestValue = value
+			ties = ties[:0]
+		}
+		if value == bestValue {
+			ties = append(ties, i)
+		}
+	}
+	return ties[s.e.rng.Intn(len(ties))]
+}
+
+// exp3Probabilities 返回 Exp3 的选择概率 (1-γ)·softmax(η·G) + γ/K，其中 η = γ/K，G 为归一化后的累计奖励估计。
+func (s *simSearch) exp3Probabilities(stats []simStats, isMaxPlayer bool) []float64 {
+	k := float64(len(stats))
+	eta := s.gamma / k
+	gains := make([]float64, len(stats))
+	maxGain := math.Inf(-1)
+	for i, st := range stats {
+		gains[i] = s.gain(st, isMaxPlayer)
+		maxGain = math.Max(maxGain, gains[i])
+	}
+	probs := make([]float64, len(stats))
+	total := 0.0
+	for i, gain := range gains {
+		probs[i] = math.Exp(eta * (gain - maxGain))
+		total += probs[i]
+	}
+	for i := range probs {
+		probs[i] = (1-s.gamma)*probs[i]/total + s.gamma/k
+	}
+	return probs
+}
+
+// update 用原始评估值更新一名玩家的走法统计，Exp3 同时累加重要性加权的评估值与权重。
+func (s *simSearch) update(st *simStats, value float64) {
+	st.visits++
+	st.value += value
+	if s.selection == Exp3 && st.prob > 0 {
+		st.gain += value / st.prob
+		st.weight += 1 / st.prob
+	}
+}
+
+// simulate 从当前局面开始双方均匀随机地同时走棋最多 aheadStep 步，返回最大化玩家视角的评估值。
+func (s *simSearch) simulate() float64 {
+	state := s.board.Clone()
+	joint, ok := state.(SimultaneousBoard)
+	if !ok {
+		joint = s.joint
+	}
+	for steps := 0; steps < s.aheadStep && !state.IsGameOver(); steps++ {
+		s.e.stats.MoveGenerations += 2
+		maxMoves := state.GetAllMoves(true)
+		minMoves := state.GetAllMoves(false)
+		if len(maxMoves) == 0 || len(minMoves) == 0 {
+			break
+		}
+		state.Move(joint.JointMove(maxMoves[s.e.rng.Intn(len(maxMoves))], minMoves[s.e.rng.Intn(len(minMoves))]))
+	}
+	s.e.stats.LeafEvals++
+	return s.observe(state.EvaluateFunc(*s.e.EvalOptions))
+}
+
+// observe 记录观察到的评估值以更新归一化范围。
+func (s *simSearch) observe(value float64) float64 {
+	s.low = math.Min(s.low, value)
+	s.high = math.Max(s.high, value)
+	return value
+}
+
+// reward 将最大化玩家视角的评估值按当前范围归一化为指定玩家在 [0, 1] 内的奖励。
+// 范围优先使用 EvalLowerBound/EvalUpperBound，否则使用目前观察到的范围；范围为空时返回 0.5。
+func (s *simSearch) reward(value float64, isMaxPlayer bool) float64 {
+	low, high := s.bounds()
+	if high <= low {
+		return 0.5
+	}
+	reward := (value - low) / (high - low)
+	if !isMaxPlayer {
+		reward = 1 - reward
+	}
+	return reward
+}
+
+// gain 返回 Exp3 按当前范围归一化后的累计奖励估计 Σr/p。归一化是仿射变换，
+// 因此可以由原始的 Σv/p 与 Σ1/p 精确还原，不受评估值范围变化的影响。
+func (s *simSearch) gain(st simStats, isMaxPlayer bool) float64 {
+	low, high := s.bounds()
+	if high <= low {
+		return 0.5 * st.weight
+	}
+	gain := (st.gain - low*st.weight) / (high - low)
+	if !isMaxPlayer {
+		gain = st.weight - gain
+	}
+	return gain
+}
+
+// bounds 返回用于归一化的评估值范围。
+func (s *simSearch) bounds() (float64, float64) {
+	opts := s.e.EvalOptions
+	if !math.IsInf(opts.EvalLowerBound, 0) && !math.IsInf(opts.EvalUpperBound, 0) {
+		return opts.EvalLowerBound, opts.EvalUpperBound
+	}
+	return s.low, s.high
+}
+
+// strategy 根据根节点上一名玩家的选择次数计算混合策略。
+// Exp3 会先扣除 γ 带来的均匀探索次数，再归一化。
+func (s *simSearch) strategy(root *simNode, isMaxPlayer bool) []MoveProbability {
+	moves, stats := root.minMoves, root.minStats
+	if isMaxPlayer {
+		moves, stats = root.maxMoves, root.maxStats
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+
+	weights := make([]float64, len(stats))
+	total := 0.0
+	for i, st := range stats {
+		weights[i] = float64(st.visits)
+		if s.selection == Exp3 {
+			weights[i] = math.Max(0, weights[i]-s.gamma*float64(root.visits)/float64(len(stats)))
+		}
+		total += weights[i]
+	}
+	strategy := make([]MoveProbability, len(moves))
+	for i, move := range moves {
+		p := 1 / float64(len(moves))
+		if total > 0 {
+			p = weights[i] / total
+		}
+		strategy[i] = MoveProbability{Move: move, Probability: p}
+	}
+	return strategy
+}
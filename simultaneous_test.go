@@ -0,0 +1,90 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+// coinMove 是猜硬币中一名玩家出示的硬币面：H 为正面，T 为反面。
+type coinMove string
+
+func (m coinMove) String() string { return string(m) }
+
+// coinJointMove 是双方同时出示的硬币面。
+type coinJointMove [2]coinMove
+
+func (m coinJointMove) String() string { return string(m[0]) + string(m[1]) }
+
+// penniesBoard 是猜硬币：双方同时出示一枚硬币，相同时最大化玩家赢 1，不同时最小化玩家赢 1。
+// 唯一的纳什均衡是双方各以 1/2 的概率出示正反面。
+type penniesBoard struct {
+	played *coinJointMove
+}
+
+func (b *penniesBoard) Print() { fmt.Println(b.played) }
+
+func (b *penniesBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() {
+		return nil
+	}
+	return []Move{coinMove("H"), coinMove("T")}
+}
+
+func (b *penniesBoard) Move(move Move) {
+	m := move.(coinJointMove)
+	b.played = &m
+}
+
+func (b *penniesBoard) UndoMove(move Move) { b.played = nil }
+
+func (b *penniesBoard) IsGameOver() bool { return b.played != nil }
+
+func (b *penniesBoard) EvaluateFunc(opts EvalOptions) float64 {
+	if b.played == nil {
+		return 0
+	}
+	if b.played[0] == b.played[1] {
+		return 1
+	}
+	return -1
+}
+
+func (b *penniesBoard) Hash() uint64 { return 0 }
+
+func (b *penniesBoard) Clone() Board { return &penniesBoard{played: b.played} }
+
+func (b *penniesBoard) JointMove(maxMove, minMove Move) Move {
+	return coinJointMove{maxMove.(coinMove), minMove.(coinMove)}
+}
+
+func TestSimultaneousUCTMatchingPennies(t *testing.T) {
+	for _, selection := range []SimultaneousSelectionType{DecoupledUCT, Exp3} {
+		for _, isMaxPlayer := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%d/max=%v", selection, isMaxPlayer), func(t *testing.T) {
+				e := NewEvaluator(SimultaneousUCT, NewEvaluatorOptions(
+					WithBoard(&penniesBoard{}),
+					WithIsMaxPlayer(isMaxPlayer),
+					WithIterations(20000),
+					WithSimultaneousSelection(selection, 0.1),
+					WithEvalBounds(-1, 1),
+					WithSeed(1),
+				))
+				result, err := e.Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.MixedStrategy) != 2 {
+					t.Fatalf("mixed strategy %v, want both coin faces", result.MixedStrategy)
+				}
+				for _, mp := range result.MixedStrategy {
+					if mp.Probability < 0.4 || mp.Probability > 0.6 {
+						t.Errorf("mixed strategy %v, want about 0.5/0.5", result.MixedStrategy)
+					}
+				}
+				if fmt.Sprint(result.MixedStrategy) != fmt.Sprint(e.MixedStrategy) {
+					t.Errorf("result strategy %v, Evaluator.MixedStrategy %v", result.MixedStrategy, e.MixedStrategy)
+				}
+			})
+		}
+	}
+}
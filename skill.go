@@ -0,0 +1,189 @@
+package gotack
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SkillLevel 描述对搜索结果的有控制的削弱，用于为休闲玩家提供不同的难度。
+// 各项可以组合使用，零值表示不启用该项。所有随机选择都来自 Seed 派生的随机数，相同的种子得到相同的走法。
+// 削弱在 MoveSelection 之后进行，只影响 BestMoves、Value 与 PV，Lines 仍为搜索得到的原始排序。
+type SkillLevel struct {
+	// MaxDepth 限制依赖深度的算法的最大搜索深度。
+	MaxDepth int
+
+	// MaxNodes 限制搜索访问的节点数，含义与 EvalOptions.MaxNodes 相同，两者都设置时取较小者。
+	MaxNodes int64
+
+	// Margin 表示根节点行棋方视角下评估值与最佳走法相差不超过 Margin 的走法都可能被选中。
+	// Temperature 为候选走法之间按 softmax 加权的温度，0 表示在候选走法中均匀选择。
+	// 两者之一大于 0 时启用：AlphaBeta/PVS 会对全部根节点走法进行 Multi-PV 搜索以得到各自的评估值，
+	// MCTS 类算法使用根节点子节点以根节点行棋方视角的平均奖励，其余算法不支持按评估值选择。
+	Margin      float64
+	Temperature float64
+
+	// BlunderRate 表示以多大的概率完全随机地走出任意一个合法走法。Value 与 PV 为该走法自己的评估值与主要变例，
+	// 搜索没有得到该走法的评估值时 Value 为走出该走法后局面的静态评估值。
+	BlunderRate float64
+
+	// VisitTemperature 表示 UCT 按访问次数的 1/VisitTemperature 次幂成比例地选择根节点走法，
+	// 1 表示按访问次数成比例，越大越随机。设置后优先于 Margin 与 Temperature。
+	VisitTemperature float64
+}
+
+// rootChoice 表示削弱时可供选择的一个根节点走法。
+type rootChoice struct {
+	move   Move
+	value  float64 // 以最大化玩家视角的评估值，MCTS 为平均奖励
+	score  float64 // 根节点行棋方视角的分数，越大越好
+	pv     []Move
+	visits int
+}
+
+// needsRootScores 检查是否需要搜索全部根节点走法的评估值。
+func (s *SkillLevel) needsRootScores() bool {
+	return s != nil && (s.Margin > 0 || s.Temperature > 0)
+}
+
+// weaken 按 SkillLevel 从搜索结果中重新选择走法，返回新的评估值与只含该走法的最佳走法，并更新主要变例。
+func (e *Evaluator) weaken(value float64, bestMoves []Move) (float64, []Move) {
+	skill := e.EvalOptions.Skill
+	if skill == nil || len(bestMoves) == 0 {
+		return value, bestMoves
+	}
+	choices := e.rootChoices()
+	index := -1
+	switch {
+	case skill.BlunderRate > 0 && e.rng.Float64() < skill.BlunderRate:
+		isMaxPlayer := e.multiPlayer || e.EvalOptions.IsMaxPlayer
+		moves := e.legalMoves(e.Board, isMaxPlayer)
+		move := moves[e.rng.Intn(len(moves))]
+		for i, choice := range choices {
+			if choice.move.String() == move.String() {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			e.pv = []Move{move}
+			e.BestMoves = []Move{move}
+			return e.moveValue(move, isMaxPlayer), e.BestMoves
+		}
+	case skill.VisitTemperature > 0 && e.tree != nil && len(choices) > 0:
+		visits := make([]int, len(choices))
+		for i, choice := range choices {
+			visits[i] = choice.visits
+		}
+		index = sampleVisits(visits, skill.VisitTemperature, e.rng)
+	case skill.needsRootScores() && len(choices) > 0:
+		scores := make([]float64, len(choices))
+		for i, choice := range choices {
+			scores[i] = choice.score
+		}
+		index = sampleMargin(scores, skill.Margin, skill.Temperature, e.rng)
+	}
+	if index < 0 {
+		return value, bestMoves
+	}
+	choice := choices[index]
+	e.pv = choice.pv
+	e.BestMoves = []Move{choice.move}
+	return choice.value, e.BestMoves
+}
+
+// moveValue 返回根局面走出 move 后不经搜索的评估值：多人棋盘为根节点行棋方的分数，其余棋盘与搜索中的叶节点相同。
+func (e *Evaluator) moveValue(move Move, isMaxPlayer bool) float64 {
+	e.Board.Move(move)
+	defer e.Board.UndoMove(move)
+	if mp, ok := e.Board.(MultiPlayerBoard); ok && e.multiPlayer {
+		return mp.PlayerScores(*e.EvalOptions)[e.rootPlayer]
+	}
+	return e.evaluateLeaf(!isMaxPlayer, 1, e.EvalOptions)
+}
+
+// rootChoices 返回本次搜索中已知评估值的根节点走法：MCTS 为访问过的根节点子节点，AlphaBeta/PVS 为 Multi-PV 的结果。
+func (e *Evaluator) rootChoices() []rootChoice {
+	var choices []rootChoice
+	if e.tree != nil {
+		for _, child := range e.tree.Children {
+			if child.Visits == 0 || child.Move == nil {
+				continue
+			}
+			choices = append(choices, rootChoice{
+				move:   child.Move,
+				value:  child.TotalReward / float64(child.Visits),
+				score:  rootPerspectiveValue(e.tree, child),
+				pv:     principalVariation(child),
+				visits: child.Visits,
+			})
+		}
+		return choices
+	}
+	for _, line := range e.lines {
+		choices = append(choices, rootChoice{
+			move:  line.Move,
+			value: line.Value,
+			score: e.rootScore(line.Value),
+			pv:    line.PV,
+		})
+	}
+	return choices
+}
+
+// sampleMargin 在分数与最高分相差不超过 margin 的下标中抽取一个：temperature 大于 0 时按 softmax(scores / temperature) 加权，
+// 否则均匀选择。
+func sampleMargin(scores []float64, margin, temperature float64, rng *rand.Rand) int {
+	best := math.Inf(-1)
+	for _, score := range scores {
+		best = math.Max(best, score)
+	}
+	var candidates []int
+	var candidateScores []float64
+	for i, score := range scores {
+		if score >= best-margin {
+			candidates = append(candidates, i)
+			candidateScores = append(candidateScores, score)
+		}
+	}
+	if temperature <= 0 {
+		return candidates[rng.Intn(len(candidates))]
+	}
+	return candidates[sampleSoftmax(candidateScores, temperature, rng)]
+}
+
+// sampleVisits 按访问次数的 1/temperature 次幂成比例地抽取一个下标，temperature 小于等于 0 时返回访问次数最多的下标。
+func sampleVisits(visits []int, temperature float64, rng *rand.Rand) int {
+	best := 0
+	for i, v := range visits {
+		if v > visits[best] {
+			best = i
+		}
+	}
+	if temperature <= 0 || visits[best] == 0 {
+		return best
+	}
+	weights := make([]float64, len(visits))
+	total := 0.0
+	for i, v := range visits {
+		weights[i] = math.Pow(float64(v)/float64(visits[best]), 1/temperature)
+		total += weights[i]
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return sampleIndex(weights, rng)
+}
+
+// nodeBudget 返回 MaxNodes 与 SkillLevel.MaxNodes 中较小的正值，都未设置时返回 0。
+func (opts *EvalOptions) nodeBudget() int64 {
+	budget := opts.MaxNodes
+	if skill := opts.Skill; skill != nil && skill.MaxNodes > 0 && (budget <= 0 || skill.MaxNodes < budget) {
+		budget = skill.MaxNodes
+	}
+	return max(budget, 0)
+}
+
+// nodesExhausted 检查本次搜索访问的节点数是否已经达到节点数上限。
+func (e *Evaluator) nodesExhausted() bool {
+	return e.nodeLimit > 0 && e.nodes >= e.nodeLimit
+}
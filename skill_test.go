@@ -0,0 +1,67 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSkillMarginMinRoot(t *testing.T) {
+	// 根节点为最小化玩家，走法 0 最好；两种削弱的候选都只包含走法 0（Temperature 使走法 1 的权重可以忽略）。
+	skills := []SkillLevel{
+		{Margin: 1},
+		{Margin: 6, Temperature: 0.1},
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, UCT} {
+		for _, skill := range skills {
+			for seed := int64(1); seed <= 10; seed++ {
+				t.Run(fmt.Sprintf("%d/%+v/%d", tt, skill, seed), func(t *testing.T) {
+					result, err := NewEvaluator(tt, NewEvaluatorOptions(
+						WithBoard(newPickBoard(1, 0, 5, 10)),
+						WithDepth(1),
+						WithIterations(300),
+						WithTimeLimit(0),
+						WithIsMaxPlayer(false),
+						WithSeed(seed),
+						WithSkill(skill),
+					)).Search()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(0) || result.Value != 0 {
+						t.Fatalf("best moves %v value %v, want [0] and 0", result.BestMoves, result.Value)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestSkillBlunderUsesChosenMoveValue(t *testing.T) {
+	scores := []float64{0, 5, 10}
+	for _, tt := range []GameTreeType{AlphaBeta, UCT} {
+		for _, multiPV := range []int{0, 3} {
+			for seed := int64(1); seed <= 10; seed++ {
+				result, err := NewEvaluator(tt, NewEvaluatorOptions(
+					WithBoard(newPickBoard(1, scores...)),
+					WithDepth(1),
+					WithIterations(300),
+					WithTimeLimit(0),
+					WithMultiPV(multiPV),
+					WithSeed(seed),
+					WithSkill(SkillLevel{BlunderRate: 1}),
+				)).Search()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(result.BestMoves) != 1 {
+					t.Fatalf("best moves %v, want a single move", result.BestMoves)
+				}
+				move := result.BestMoves[0].(pickMove)
+				if result.Value != scores[move] || len(result.PV) != 1 || result.PV[0] != move {
+					t.Errorf("%d/multipv=%d/seed=%d: move %v value %v pv %v, want value %v and pv [%v]",
+						tt, multiPV, seed, move, result.Value, result.PV, scores[move], move)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package gotack
+
+import (
+	"fmt"
+	"time"
+)
+
+// SearchStats 表示一次搜索的统计信息，用于诊断剪枝效率与搜索开销。各项只统计所选算法实际涉及的部分，其余为 0。
+type SearchStats struct {
+	// Nodes 为访问的节点数，与 MaxNodes 的计数方式相同：MCTS 类算法为模拟次数，ProofNumber/DFPN 为展开的节点数。
+	Nodes int64
+	// LeafEvals 为叶节点评估（EvaluateFunc、PlayerScores 或终局结果）的次数。
+	LeafEvals int64
+	// MoveGenerations 为 GetAllMoves 的调用次数。
+	MoveGenerations int64
+	// Cutoffs 为 Alpha-Beta 类算法在玩家节点发生 beta 剪枝的次数，FirstMoveCutoffs 为其中第一个走法即发生剪枝的次数。
+	Cutoffs          int64
+	FirstMoveCutoffs int64
+	// TTHits 为置换表（DFPN 的置换表）命中的次数。
+	TTHits int64
+	// TablebaseHits 为残局库探测命中的次数。
+	TablebaseHits int64
+	// ReSearches 为 PVS 零窗口搜索失败后以完整窗口重新搜索的次数。
+	ReSearches int64
+	// Playouts 为 MCTS 类算法的模拟次数。
+	Playouts int64
+	// TreeSize 为 MCTS 与证明数搜索建立的树节点数，DFPN 为置换表的大小。
+	TreeSize int64
+	// MaxDepth 为搜索到达的最大深度（距离根节点的步数）。
+	MaxDepth int
+	// Elapsed 为搜索使用的时间。
+	Elapsed time.Duration
+	// NPS 为每秒访问的节点数。
+	NPS int64
+}
+
+// FirstMoveCutoffRate 返回第一个走法即发生剪枝的比例，反映走法排序的质量；没有剪枝时返回 0。
+func (s SearchStats) FirstMoveCutoffRate() float64 {
+	if s.Cutoffs == 0 {
+		return 0
+	}
+	return float64(s.FirstMoveCutoffs) / float64(s.Cutoffs)
+}
+
+// String 以单行文本返回统计信息。
+func (s SearchStats) String() string {
+	return fmt.Sprintf("nodes=%d leaf=%d movegen=%d cutoffs=%d first=%.1f%% tt=%d tb=%d research=%d playouts=%d tree=%d depth=%d nps=%d",
+		s.Nodes, s.LeafEvals, s.MoveGenerations, s.Cutoffs, 100*s.FirstMoveCutoffRate(), s.TTHits, s.TablebaseHits,
+		s.ReSearches, s.Playouts, s.TreeSize, s.MaxDepth, s.NPS)
+}
+
+// merge 累加并行副本的统计信息。
+func (s *SearchStats) merge(other SearchStats) {
+	s.LeafEvals += other.LeafEvals
+	s.MoveGenerations += other.MoveGenerations
+	s.Cutoffs += other.Cutoffs
+	s.FirstMoveCutoffs += other.FirstMoveCutoffs
+	s.TTHits += other.TTHits
+	s.TablebaseHits += other.TablebaseHits
+	s.ReSearches += other.ReSearches
+	s.Playouts += other.Playouts
+	s.TreeSize += other.TreeSize
+	s.MaxDepth = max(s.MaxDepth, other.MaxDepth)
+}
+
+// cutoff 记录一次 beta 剪枝，index 为发生剪枝的走法在走法列表中的下标。
+func (e *Evaluator) cutoff(index int) {
+	e.stats.Cutoffs++
+	if index == 0 {
+		e.stats.FirstMoveCutoffs++
+	}
+}
+
+// reachDepth 记录搜索到达了距离根节点 ply 步的局面。
+func (e *Evaluator) reachDepth(ply int) {
+	e.stats.MaxDepth = max(e.stats.MaxDepth, ply)
+}
+
+// searchStats 返回本次搜索的统计信息，并补全节点数、用时与 NPS。
+func (e *Evaluator) searchStats() SearchStats {
+	stats := e.stats
+	stats.Nodes = e.nodes
+	stats.Elapsed = e.tm.elapsed()
+	if stats.Elapsed > 0 {
+		stats.NPS = int64(float64(stats.Nodes) / stats.Elapsed.Seconds())
+	}
+	return stats
+}
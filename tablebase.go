@@ -0,0 +1,441 @@
+package gotack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// tablebaseMagic 是残局库文件的文件头标识。
+var tablebaseMagic = [4]byte{'G', 'T', 'T', 'B'}
+
+const (
+	tablebaseVersion     = 1
+	maxTablebaseDistance = 1<<14 - 1 // 编码中距离占用低 14 位
+)
+
+var (
+	// ErrInvalidTablebase 表示残局库文件的格式不正确。
+	ErrInvalidTablebase = errors.New("gotack: invalid tablebase file")
+
+	// ErrIncompleteEnumeration 表示 PositionEnumerator 枚举的局面在走法下不封闭或超出了完美索引的范围。
+	ErrIncompleteEnumeration = errors.New("gotack: enumerated positions are incomplete")
+)
+
+// TablebaseEntry 表示残局库中一个局面的精确结果。
+type TablebaseEntry struct {
+	// Result 为以该局面行棋方视角的结果。
+	Result GameResult
+	// Distance 为双方最优走法下到达终局的步数：胜方尽快取胜，负方尽量拖延；和棋为 0。
+	Distance int
+}
+
+// Tablebase 是逆向分析得到的残局库，保存每个局面的胜负结果与距离终局的步数。
+// 局面按 Board.Hash() 升序存储，棋盘实现了 PerfectIndexer 时按完美索引存储。
+// 通过 WithTablebase 设置后，AlphaBeta、PVS 与 UCT 会在搜索中查询残局库。
+type Tablebase struct {
+	indexed bool     // 是否按 PerfectIndexer 的下标存储
+	keys    []uint64 // 按升序排列的局面哈希，仅在哈希存储时使用
+	values  []uint16 // 编码后的结果：高 2 位为 GameResult，低 14 位为距离，0 表示没有记录
+}
+
+// tbNode 是逆向分析中的一个局面。
+type tbNode struct {
+	board       Board
+	isMaxPlayer bool
+	key         uint64
+	result      GameResult
+	distance    int
+	remaining   int   // 尚未判定为对手获胜的后继局面数
+	maxDistance int   // 已判定为对手获胜的后继局面中最大的距离加一
+	parents     []int // 前驱局面的下标，棋盘实现了 PredecessorBoard 时不使用
+}
+
+// tbGenerator 通过逆向分析求解全部枚举的局面。
+type tbGenerator struct {
+	e            *Evaluator
+	indexed      bool
+	predecessors bool
+	nodes        []*tbNode
+	index        map[uint64]int // 局面键到 nodes 下标的映射
+	queue        []int          // 按距离递增的已判定胜负的局面
+}
+
+// GenerateTablebase 对 opts.Board 枚举的全部局面进行逆向分析，求出每个局面的精确胜负与距离终局的步数。
+// 参数:
+//   - opts: 评估选项，opts.Board 需要实现 PositionEnumerator；终局结果来自 ResultBoard，
+//     未实现时按 EvaluateFunc 的符号判断；非终局却没有合法走法的局面按 NoMoves 规则处理，NoMovesEvaluate 同样按 EvaluateFunc 的符号判断。
+//
+// 返回值:
+//   - *Tablebase: 生成的残局库。从终局出发按距离逐层回推：存在走向负局面的走法即为胜，
+//     全部走法都走向胜局面即为负，其余（包括循环局面）均为和棋。
+//   - error: 棋盘未实现 PositionEnumerator 时返回 ErrUnsupportedBoard，
+//     枚举的局面不完整时返回 ErrIncompleteEnumeration。
+//
+// 逆向分析需要在内存中保存全部局面，只适用于小型博弈或子力很少的残局。
+func GenerateTablebase(opts *EvalOptions) (*Tablebase, error) {
+	enumerator, ok := opts.Board.(PositionEnumerator)
+	if !ok {
+		return nil, fmt.Errorf("%w: PositionEnumerator is required", ErrUnsupportedBoard)
+	}
+	_, indexed := opts.Board.(PerfectIndexer)
+	_, predecessors := opts.Board.(PredecessorBoard)
+	g := &tbGenerator{
+		e:            NewEvaluator(AlphaBeta, opts),
+		indexed:      indexed,
+		predecessors: predecessors,
+		index:        make(map[uint64]int),
+	}
+	enumerator.EnumeratePositions(func(board Board, isMaxPlayer bool) {
+		key := g.key(board)
+		if _, ok := g.index[key]; ok {
+			return
+		}
+		g.index[key] = len(g.nodes)
+		g.nodes = append(g.nodes, &tbNode{board: board.Clone(), isMaxPlayer: isMaxPlayer, key: key})
+	})
+	if err := g.link(); err != nil {
+		return nil, err
+	}
+	g.propagate()
+	return g.tablebase()
+}
+
+// key 返回局面在残局库中的键：完美索引或哈希。
+func (g *tbGenerator) key(board Board) uint64 {
+	if g.indexed {
+		return board.(PerfectIndexer).PositionIndex()
+	}
+	return board.Hash()
+}
+
+// link 判定全部终局局面，并统计每个非终局局面的后继局面数；未实现 PredecessorBoard 时同时记录前驱局面。
+func (g *tbGenerator) link() error {
+	for i, node := range g.nodes {
+		board := node.board
+		if board.IsGameOver() {
+			node.result = g.e.terminalResult(board, node.isMaxPlayer)
+			g.settle(i)
+			continue
+		}
+		moves := g.e.legalMoves(board, node.isMaxPlayer)
+		if len(moves) == 0 {
+			switch g.e.EvalOptions.NoMoves {
+			case NoMovesEvaluate:
+				node.result = g.e.evaluatedResult(board, node.isMaxPlayer)
+			case NoMovesLoss:
+				node.result = Loss
+			case NoMovesDraw:
+				node.result = Draw
+			default:
+				return ErrNoLegalMoves
+			}
+			g.settle(i)
+			continue
+		}
+
+		seen := make(map[int]bool, len(moves))
+		for _, move := range moves {
+			board.Move(move)
+			key := g.key(board)
+			board.UndoMove(move)
+			child, ok := g.index[key]
+			if !ok {
+				return fmt.Errorf("%w: successor %d of an enumerated position is missing", ErrIncompleteEnumeration, key)
+			}
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			node.remaining++
+			if !g.predecessors {
+				g.nodes[child].parents = append(g.nodes[child].parents, i)
+			}
+		}
+	}
+	return nil
+}
+
+// settle 将已判定的终局局面加入待回推的队列，和棋与未知结果不需要回推。
+func (g *tbGenerator) settle(i int) {
+	switch g.nodes[i].result {
+	case Win, Loss:
+		g.queue = append(g.queue, i)
+	default:
+		g.nodes[i].result = Draw
+	}
+}
+
+// propagate 按距离从小到大回推胜负：局面的某个后继为负即为胜，距离取最小；
+// 全部后继都为胜即为负，距离取最大。最后仍未判定的局面均为和棋。
+func (g *tbGenerator) propagate() {
+	for head := 0; head < len(g.queue); head++ {
+		node := g.nodes[g.queue[head]]
+		for _, p := range g.parentsOf(node) {
+			parent := g.nodes[p]
+			if parent.result != Unknown {
+				continue
+			}
+			switch node.result {
+			case Loss:
+				parent.result = Win
+				parent.distance = node.distance + 1
+				g.queue = append(g.queue, p)
+			case Win:
+				parent.maxDistance = max(parent.maxDistance, node.distance+1)
+				parent.remaining--
+				if parent.remaining == 0 {
+					parent.result = Loss
+					parent.distance = parent.maxDistance
+					g.queue = append(g.queue, p)
+				}
+			}
+		}
+	}
+	for _, node := range g.nodes {
+		if node.result == Unknown {
+			node.result = Draw
+		}
+	}
+}
+
+// parentsOf 返回局面的前驱局面下标，实现了 PredecessorBoard 的棋盘按需生成，重复的前驱只返回一次。
+func (g *tbGenerator) parentsOf(node *tbNode) []int {
+	if !g.predecessors {
+		return node.parents
+	}
+	var parents []int
+	seen := make(map[int]bool)
+	for _, pred := range node.board.(PredecessorBoard).Predecessors(node.isMaxPlayer) {
+		p, ok := g.index[g.key(pred)]
+		if !ok || seen[p] {
+			continue
+		}
+		seen[p] = true
+		parents = append(parents, p)
+	}
+	return parents
+}
+
+// tablebase 将求解结果编码为残局库。
+func (g *tbGenerator) tablebase() (*Tablebase, error) {
+	tb := &Tablebase{indexed: g.indexed}
+	if g.indexed {
+		tb.values = make([]uint16, g.e.Board.(PerfectIndexer).PositionCount())
+		for _, node := range g.nodes {
+			if node.key >= uint64(len(tb.values)) {
+				return nil, fmt.Errorf("%w: index %d is out of range", ErrIncompleteEnumeration, node.key)
+			}
+			tb.values[node.key] = encodeTablebaseEntry(node.result, node.distance)
+		}
+		return tb, nil
+	}
+
+	nodes := append([]*tbNode(nil), g.nodes...)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].key < nodes[j].key
+	})
+	tb.keys = make([]uint64, len(nodes))
+	tb.values = make([]uint16, len(nodes))
+	for i, node := range nodes {
+		tb.keys[i] = node.key
+		tb.values[i] = encodeTablebaseEntry(node.result, node.distance)
+	}
+	return tb, nil
+}
+
+// encodeTablebaseEntry 将结果与距离编码为 16 位整数，超出范围的距离按最大值保存。
+func encodeTablebaseEntry(result GameResult, distance int) uint16 {
+	return uint16(result)<<14 | uint16(min(distance, maxTablebaseDistance))
+}
+
+// Probe 查询局面的精确结果，局面不在残局库中时返回 false。
+func (tb *Tablebase) Probe(board Board) (TablebaseEntry, bool) {
+	var value uint16
+	if tb.indexed {
+		indexer, ok := board.(PerfectIndexer)
+		if !ok {
+			return TablebaseEntry{}, false
+		}
+		index := indexer.PositionIndex()
+		if index >= uint64(len(tb.values)) {
+			return TablebaseEntry{}, false
+		}
+		value = tb.values[index]
+	} else {
+		hash := board.Hash()
+		i := sort.Search(len(tb.keys), func(i int) bool {
+			return tb.keys[i] >= hash
+		})
+		if i == len(tb.keys) || tb.keys[i] != hash {
+			return TablebaseEntry{}, false
+		}
+		value = tb.values[i]
+	}
+	if value == 0 {
+		return TablebaseEntry{}, false
+	}
+	return TablebaseEntry{Result: GameResult(value >> 14), Distance: int(value & maxTablebaseDistance)}, true
+}
+
+// Len 返回残局库中保存的局面数。
+func (tb *Tablebase) Len() int {
+	if !tb.indexed {
+		return len(tb.keys)
+	}
+	count := 0
+	for _, value := range tb.values {
+		if value != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// tablebaseHeader 是残局库文件的文件头。
+type tablebaseHeader struct {
+	Magic   [4]byte
+	Version uint8
+	Indexed uint8
+	Count   uint64
+}
+
+// Save 将残局库以紧凑的二进制格式（小端序）写入 w：文件头之后，哈希存储依次写入升序的 8 字节哈希与 2 字节结果，
+// 完美索引存储只写入按下标排列的 2 字节结果。
+func (tb *Tablebase) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	header := tablebaseHeader{Magic: tablebaseMagic, Version: tablebaseVersion, Count: uint64(len(tb.values))}
+	if tb.indexed {
+		header.Indexed = 1
+	}
+	if err := binary.Write(bw, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if !tb.indexed {
+		if err := binary.Write(bw, binary.LittleEndian, tb.keys); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(bw, binary.LittleEndian, tb.values); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// SaveFile 将残局库保存到指定路径的文件。
+func (tb *Tablebase) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tb.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadTablebase 从 r 中读取由 Save 写入的残局库。
+// 文件头中的局面数不会被直接用来分配内存：r 支持 io.Seeker 时先与剩余数据的长度核对，
+// 数据本身按块读取，内存随实际读到的数据增长，因此损坏或恶意构造的文件只会返回 ErrInvalidTablebase。
+func LoadTablebase(r io.Reader) (*Tablebase, error) {
+	var header tablebaseHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTablebase, err)
+	}
+	if header.Magic != tablebaseMagic || header.Version != tablebaseVersion || header.Indexed > 1 {
+		return nil, ErrInvalidTablebase
+	}
+	entrySize := uint64(2)
+	if header.Indexed == 0 {
+		entrySize += 8
+	}
+	if seeker, ok := r.(io.Seeker); ok {
+		remaining, err := remainingBytes(seeker)
+		if err != nil {
+			return nil, err
+		}
+		if header.Count > remaining/entrySize {
+			return nil, fmt.Errorf("%w: header declares %d entries but only %d bytes follow", ErrInvalidTablebase, header.Count, remaining)
+		}
+	}
+
+	br := bufio.NewReader(r)
+	tb := &Tablebase{indexed: header.Indexed == 1}
+	var err error
+	if !tb.indexed {
+		if tb.keys, err = readChunked[uint64](br, header.Count); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTablebase, err)
+		}
+		if !sort.SliceIsSorted(tb.keys, func(i, j int) bool { return tb.keys[i] < tb.keys[j] }) {
+			return nil, ErrInvalidTablebase
+		}
+	}
+	if tb.values, err = readChunked[uint16](br, header.Count); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTablebase, err)
+	}
+	return tb, nil
+}
+
+// remainingBytes 返回 seeker 当前位置之后剩余的字节数，并恢复原来的位置。
+func remainingBytes(seeker io.Seeker) (uint64, error) {
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := seeker.Seek(current, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return uint64(max(0, end-current)), nil
+}
+
+// tablebaseChunk 是读取残局库数据时每次读取的元素数。
+const tablebaseChunk = 1 << 16
+
+// readChunked 以小端序分块读取 count 个元素，数据不足时返回错误而不会预先分配 count 个元素。
+func readChunked[T uint16 | uint64](r io.Reader, count uint64) ([]T, error) {
+	data := make([]T, 0, min(count, tablebaseChunk))
+	buf := make([]T, min(count, tablebaseChunk))
+	for remaining := count; remaining > 0; {
+		n := min(remaining, uint64(len(buf)))
+		if err := binary.Read(r, binary.LittleEndian, buf[:n]); err != nil {
+			return nil, err
+		}
+		data = append(data, buf[:n]...)
+		remaining -= n
+	}
+	return data, nil
+}
+
+// LoadTablebaseFile 从指定路径的文件中读取残局库。
+func LoadTablebaseFile(path string) (*Tablebase, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadTablebase(f)
+}
+
+// probeTablebase 在非根节点查询 WithTablebase 设置的残局库，命中时返回以最大化玩家视角的评估值。
+// ply 为局面距离根节点的步数，胜负按 ply 与残局库中的距离之和转换为胜负评估值，和棋为 0。
+func (e *Evaluator) probeTablebase(board Board, isMaxPlayer bool, ply int) (float64, bool) {
+	tb := e.EvalOptions.Tablebase
+	if tb == nil || ply == 0 || e.multiPlayer {
+		return 0, false
+	}
+	entry, ok := tb.Probe(board)
+	if !ok {
+		return 0, false
+	}
+	e.stats.TablebaseHits++
+	return resultValue(entry.Result, isMaxPlayer, ply+entry.Distance), true
+}
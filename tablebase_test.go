@@ -0,0 +1,75 @@
+package gotack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestTablebaseSaveLoadRoundTrip(t *testing.T) {
+	for _, tb := range []*Tablebase{
+		{keys: []uint64{3, 7, 11}, values: []uint16{1, 2, 3}},
+		{indexed: true, values: []uint16{4, 5, 6, 7}},
+	} {
+		var buf bytes.Buffer
+		if err := tb.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadTablebase(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(loaded.keys, loaded.values, loaded.indexed) != fmt.Sprint(tb.keys, tb.values, tb.indexed) {
+			t.Fatalf("loaded %v %v, want %v %v", loaded.keys, loaded.values, tb.keys, tb.values)
+		}
+	}
+}
+
+func TestLoadTablebaseRejectsOversizedCount(t *testing.T) {
+	for _, indexed := range []uint8{0, 1} {
+		var buf bytes.Buffer
+		header := tablebaseHeader{Magic: tablebaseMagic, Version: tablebaseVersion, Indexed: indexed, Count: 1 << 62}
+		if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(make([]byte, 64))
+		data := buf.Bytes()
+
+		readers := map[string]io.Reader{
+			"seeker": bytes.NewReader(data),
+			"stream": io.MultiReader(bytes.NewReader(data)),
+		}
+		for name, r := range readers {
+			if _, err := LoadTablebase(r); !errors.Is(err, ErrInvalidTablebase) {
+				t.Errorf("indexed=%d %s: error %v, want ErrInvalidTablebase", indexed, name, err)
+			}
+		}
+	}
+}
+
+func TestTablebaseHitsCountedSeparately(t *testing.T) {
+	board := newPickBoard(1, 1, 2, 3)
+	tb := &Tablebase{}
+	for _, m := range board.GetAllMoves(true) {
+		board.Move(m)
+		tb.keys = append(tb.keys, board.Hash())
+		board.UndoMove(m)
+	}
+	slices.Sort(tb.keys)
+	for range tb.keys {
+		tb.values = append(tb.values, encodeTablebaseEntry(Draw, 0))
+	}
+	for _, tt := range []GameTreeType{AlphaBeta, PVS} {
+		result, err := NewEvaluator(tt, NewEvaluatorOptions(WithBoard(board), WithDepth(1), WithTablebase(tb))).Search()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Stats.TablebaseHits != int64(len(tb.keys)) || result.Stats.TTHits != 0 {
+			t.Errorf("%d: %d tablebase hits and %d TT hits, want %d and 0", tt, result.Stats.TablebaseHits, result.Stats.TTHits, len(tb.keys))
+		}
+	}
+}
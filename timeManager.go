@@ -0,0 +1,250 @@
+package gotack
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMovesToGo  = 30                     // 未指定 MovesToGo 时估计的剩余步数
+	maxClockOverhead  = 200 * time.Millisecond // 为通信与调度预留的最长时间
+	hardLimitFactor   = 4                      // 硬限制最多为软限制的倍数
+	extensionFactor   = 1.5                    // 最佳走法不稳定或评估值下降时软限制的延长倍数
+	timeCheckInterval = 256                    // UCT 每隔多少次迭代检查一次软限制与提前停止
+	dominantShare     = 0.9                    // 用时过半后最佳子节点访问占比达到该值即提前停止
+)
+
+// forever 表示不限制时间。
+const forever = time.Duration(math.MaxInt64)
+
+// TimeControl 表示对局时钟的状态，用于为当前这一步分配思考时间。
+type TimeControl struct {
+	// Remaining 为行棋方的剩余时间。
+	Remaining time.Duration
+	// Increment 为每走一步增加的时间。
+	Increment time.Duration
+	// MovesToGo 为下一次加时前还需要走的步数，0 表示按剩余时间的固定比例分配。
+	MovesToGo int
+}
+
+// Allocate 为当前这一步分配软限制与硬限制。
+// 先从剩余时间中预留少量时间，再按剩余步数平均分配并加上大部分加时得到软限制；
+// 硬限制为软限制的数倍，但不超过可用时间的三分之一（最后一步前可以用完全部可用时间）。
+// 搜索在软限制到达后的下一个检查点停止，最佳走法不稳定时可以延长到硬限制。
+func (tc TimeControl) Allocate() (soft, hard time.Duration) {
+	usable := tc.Remaining - min(tc.Remaining/20, maxClockOverhead)
+	if usable <= 0 {
+		return 0, 0
+	}
+	movesToGo := tc.MovesToGo
+	if movesToGo <= 0 {
+		movesToGo = defaultMovesToGo
+	}
+	soft = min(usable/time.Duration(movesToGo)+tc.Increment*3/4, usable)
+	hard = min(soft*hardLimitFactor, max(soft, usable/3))
+	return soft, hard
+}
+
+// timeManager 管理一次搜索的用时。
+type timeManager struct {
+	start   time.Time
+	soft    time.Duration // 目标用时，可以因局面不稳定而延长
+	hard    time.Duration // 绝对上限
+	managed bool          // 是否由 MoveTime 或 Clock 控制，此时启用迭代加深、延时与提前停止
+	stop    *atomic.Bool  // 外部停止标志，设置后立即视为超时
+}
+
+// newTimeManager 为本次搜索创建时间管理器。后台思考与分析时不限制时间，并启用迭代加深，直到停止标志被设置。
+func (e *Evaluator) newTimeManager() *timeManager {
+	tm := newTimeManager(e.EvalOptions)
+	tm.stop = e.stopFlag
+	if e.infinite {
+		tm.soft, tm.hard = forever, forever
+		tm.managed = true
+	}
+	return tm
+}
+
+// newTimeManager 根据评估选项创建时间管理器：Clock 优先，其次是 MoveTime，最后是以秒为单位的 TimeLimit。
+func newTimeManager(opts *EvalOptions) *timeManager {
+	tm := &timeManager{start: time.Now(), soft: forever, hard: forever}
+	switch {
+	case opts.Clock != nil:
+		tm.soft, tm.hard = opts.Clock.Allocate()
+		tm.managed = true
+	case opts.MoveTime > 0:
+		tm.soft, tm.hard = opts.MoveTime, opts.MoveTime
+		tm.managed = true
+	case opts.TimeLimit > 0:
+		tm.soft = time.Duration(opts.TimeLimit) * time.Second
+		tm.hard = tm.soft
+	}
+	return tm
+}
+
+// elapsed 返回搜索已经使用的时间。
+func (tm *timeManager) elapsed() time.Duration {
+	return time.Since(tm.start)
+}
+
+// softExpired 检查是否已经达到软限制或被外部停止。
+func (tm *timeManager) softExpired() bool {
+	return tm.stopped() || tm.elapsed() >= tm.soft
+}
+
+// hardExpired 检查是否已经达到硬限制或被外部停止。
+func (tm *timeManager) hardExpired() bool {
+	return tm.stopped() || tm.elapsed() >= tm.hard
+}
+
+// stopped 检查外部停止标志是否已被设置。
+func (tm *timeManager) stopped() bool {
+	return tm.stop != nil && tm.stop.Load()
+}
+
+// extend 将软限制延长 extensionFactor 倍，但不超过硬限制。
+func (tm *timeManager) extend() {
+	if tm.soft < tm.hard {
+		tm.soft = min(time.Duration(float64(tm.soft)*extensionFactor), tm.hard)
+	}
+}
+
+// stopping 检查迭代加深是否因时间或节点数耗尽需要中止当前迭代。
+// 深度 1 的迭代不会因时间中止，以保证有可用的走法；节点数上限在任何深度都生效。
+func (e *Evaluator) stopping() bool {
+	if !e.stopped && e.deepening && (e.Depth > 1 && e.tm.hardExpired() || e.nodesExhausted()) {
+		e.stopped = true
+	}
+	return e.stopped
+}
+
+// depthSearch 执行依赖深度的算法 search：deepen 为 true 或设置了节点数上限时进行迭代加深，否则直接搜索 EvalOptions.Depth 层。
+func (e *Evaluator) depthSearch(search func(depth int) (float64, []Move), deepen bool) (float64, []Move) {
+	if deepen || e.nodeLimit > 0 {
+		return e.iterativeDeepening(search)
+	}
+	value, bestMoves := search(e.EvalOptions.Depth)
+	e.pv = append([]Move(nil), e.pvTable[0]...)
+	return value, bestMoves
+}
+
+// iterativeDeepening 在 MoveTime、Clock 或节点数上限控制下进行迭代加深，EvalOptions.Depth 为最大深度。
+// 每完成一次迭代保存其结果，达到软限制后不再开始新的迭代，达到硬限制时中止当前迭代并使用上一次完整迭代的结果。
+// 节点数上限在深度 1 的迭代中耗尽时，使用根节点已完成的子节点中最好的走法。
+// 最佳走法与上一次迭代不同，或根节点行棋方的评估值下降超过 ScoreDropMargin 时延长软限制。
+// 设置了节点数上限而没有 MoveTime 与 Clock 时忽略 TimeLimit，使结果只取决于节点数而与机器速度无关。
+func (e *Evaluator) iterativeDeepening(search func(depth int) (float64, []Move)) (float64, []Move) {
+	maxDepth := e.Depth
+	e.deepening = true
+	defer func() { e.Depth, e.deepening = maxDepth, false }()
+	if e.nodeLimit > 0 && !e.tm.managed {
+		e.tm.soft, e.tm.hard = forever, forever
+	}
+
+	var value float64
+	var bestMoves []Move
+	for depth := 1; depth <= maxDepth; depth++ {
+		e.Depth = depth
+		v, moves := search(depth)
+		if e.stopped {
+			if depth == 1 && len(moves) > 0 {
+				// 深度 1 的迭代被节点数上限中止，使用已完成的根节点子节点中最好的走法
+				value, bestMoves = v, moves
+				e.pv = append(e.pv[:0], e.pvTable[0]...)
+			}
+			break
+		}
+		if bestMoves != nil && len(moves) > 0 {
+			if moves[0].String() != bestMoves[0].String() {
+				e.tm.extend()
+			} else if margin := e.EvalOptions.ScoreDropMargin; margin > 0 && e.rootScore(value)-e.rootScore(v) > margin {
+				e.tm.extend()
+			}
+		}
+		value, bestMoves = v, moves
+		e.pv = append(e.pv[:0], e.pvTable[0]...)
+		e.lastDepth = depth
+		if e.onInfo != nil {
+			e.onInfo(e.searchInfo(depth, value, e.pv, nil))
+		}
+		if e.tm.softExpired() || e.nodesExhausted() {
+			break
+		}
+	}
+	if e.lastDepth == 0 && e.stopped && bestMoves == nil {
+		// 节点数在根节点的第一个子节点完成之前就已耗尽，退而使用根局面的静态评估与第一个合法走法
+		value, bestMoves = e.staticValue(), e.firstMove()
+		e.pv = append(e.pv[:0], bestMoves...)
+	}
+	e.BestMoves = bestMoves
+	return value, bestMoves
+}
+
+// firstMove 返回只含根局面第一个合法走法的切片，根局面为机会节点或没有合法走法时返回 nil。
+func (e *Evaluator) firstMove() []Move {
+	if _, ok := chanceOutcomes(e.Board); ok {
+		return nil
+	}
+	isMaxPlayer := e.EvalOptions.IsMaxPlayer
+	if e.multiPlayerTree() {
+		isMaxPlayer = e.isRootPlayerToMove(e.Board.(MultiPlayerBoard))
+	}
+	moves := e.rootMoves(e.legalMoves(e.Board, isMaxPlayer))
+	if len(moves) == 0 {
+		return nil
+	}
+	return moves[:1]
+}
+
+// rootScore 将以最大化玩家视角的评估值转换为根节点行棋方视角。
+func (e *Evaluator) rootScore(value float64) float64 {
+	if !e.EvalOptions.IsMaxPlayer {
+		return 0 - value // 避免和棋得到 -0
+	}
+	return value
+}
+
+// uctShouldStop 在 UCT 的检查点判断是否停止搜索，iteration 为已完成的迭代次数，iterations 为迭代上限。
+// 达到软限制时，若最佳子节点与上一个检查点不同则延长软限制，否则停止；
+// 在 MoveTime 或 Clock 控制下，若剩余的模拟无论如何分配都无法改变访问次数最多的子节点，
+// 或用时过半后最佳子节点的访问占比达到 dominantShare，则提前停止。
+func (e *Evaluator) uctShouldStop(root *Node, lastBest **Node, iteration, iterations int) bool {
+	tm := e.tm
+	if tm.stopped() {
+		return true
+	}
+	best, second := 0, 0
+	var bestChild *Node
+	for _, child := range root.Children {
+		if child.Visits > best {
+			best, second = child.Visits, best
+			bestChild = child
+		} else if child.Visits > second {
+			second = child.Visits
+		}
+	}
+	changed := *lastBest != nil && bestChild != *lastBest
+	*lastBest = bestChild
+
+	if tm.softExpired() {
+		if !changed || !tm.managed || tm.soft >= tm.hard {
+			return true
+		}
+		tm.extend()
+	}
+	if !tm.managed || bestChild == nil {
+		return false
+	}
+
+	elapsed := tm.elapsed()
+	remaining := iterations - iteration
+	if elapsed > 0 && tm.hard < forever {
+		rate := float64(iteration) / elapsed.Seconds()
+		remaining = min(remaining, int(rate*(tm.hard-elapsed).Seconds())+1)
+	}
+	if best-second > remaining {
+		return true
+	}
+	return elapsed >= tm.soft/2 && root.Visits > 0 && float64(best) >= dominantShare*float64(root.Visits)
+}
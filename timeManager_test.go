@@ -0,0 +1,135 @@
+package gotack
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTimeControlAllocate(t *testing.T) {
+	tests := []struct {
+		tc         TimeControl
+		soft, hard time.Duration
+	}{
+		// 预留 200ms 后按默认的 30 步平均分配，硬限制为软限制的 4 倍
+		{TimeControl{Remaining: time.Minute}, 1993333333, 7973333332},
+		// 加时的 3/4 计入软限制，硬限制不超过可用时间的三分之一
+		{TimeControl{Remaining: 10 * time.Second, Increment: time.Second, MovesToGo: 5}, 2710000000, 3266666666},
+		// 下一次加时前只剩一步时可以用完全部可用时间
+		{TimeControl{Remaining: 2 * time.Second, MovesToGo: 1}, 1900 * time.Millisecond, 1900 * time.Millisecond},
+		// 不足一秒时按剩余时间的 1/20 预留
+		{TimeControl{Remaining: 500 * time.Millisecond}, 15833333, 63333332},
+		{TimeControl{}, 0, 0},
+	}
+	for _, tt := range tests {
+		soft, hard := tt.tc.Allocate()
+		if soft != tt.soft || hard != tt.hard {
+			t.Errorf("%+v: soft %v hard %v, want %v and %v", tt.tc, soft, hard, tt.soft, tt.hard)
+		}
+	}
+}
+
+func TestTimeControlAllocateLimits(t *testing.T) {
+	for _, remaining := range []time.Duration{10 * time.Millisecond, 300 * time.Millisecond, 5 * time.Second, time.Hour} {
+		for _, increment := range []time.Duration{0, 100 * time.Millisecond, 2 * time.Second} {
+			for _, movesToGo := range []int{0, 1, 2, 40} {
+				tc := TimeControl{Remaining: remaining, Increment: increment, MovesToGo: movesToGo}
+				t.Run(fmt.Sprint(tc), func(t *testing.T) {
+					soft, hard := tc.Allocate()
+					usable := remaining - min(remaining/20, maxClockOverhead)
+					if soft <= 0 || soft > hard || hard > usable {
+						t.Fatalf("soft %v hard %v with %v usable", soft, hard, usable)
+					}
+					if hard > max(soft, usable/3) || hard > soft*hardLimitFactor {
+						t.Fatalf("hard %v exceeds max(soft, usable/3) = %v or %d×soft", hard, max(soft, usable/3), hardLimitFactor)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestTimeManagerExtend(t *testing.T) {
+	tm := &timeManager{soft: time.Second, hard: 2 * time.Second}
+	tm.extend()
+	if tm.soft != 1500*time.Millisecond {
+		t.Fatalf("soft %v after one extension, want 1.5s", tm.soft)
+	}
+	tm.extend()
+	if tm.soft != tm.hard {
+		t.Fatalf("soft %v after two extensions, want the hard limit %v", tm.soft, tm.hard)
+	}
+
+	tm = &timeManager{soft: time.Second, hard: time.Second}
+	tm.extend()
+	if tm.soft != time.Second {
+		t.Fatalf("soft %v, want it unchanged when it equals the hard limit", tm.soft)
+	}
+}
+
+func TestIterativeDeepeningExtendsSoftLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		moves      []int
+		values     []float64
+		extensions int
+	}{
+		{"stable", []int{0, 0, 0}, []float64{10, 10, 10}, 0},
+		{"best move changes", []int{0, 1, 0}, []float64{10, 10, 10}, 2},
+		{"score drops", []int{0, 0, 0}, []float64{100, 50, 45}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEvaluator(AlphaBeta, NewEvaluatorOptions(
+				WithBoard(newPickBoard(3, 1, 2)),
+				WithDepth(3),
+				WithClock(time.Hour, 0, 0),
+				WithScoreDropMargin(20),
+			))
+			e.tm = e.newTimeManager()
+			e.pvTable = make([][]Move, 4)
+			soft := e.tm.soft
+			e.iterativeDeepening(func(depth int) (float64, []Move) {
+				return tt.values[depth-1], []Move{pickMove(tt.moves[depth-1])}
+			})
+			want := soft
+			for range tt.extensions {
+				want = min(time.Duration(float64(want)*extensionFactor), e.tm.hard)
+			}
+			if e.tm.soft != want {
+				t.Fatalf("soft limit %v, want %v after %d extensions of %v", e.tm.soft, want, tt.extensions, soft)
+			}
+		})
+	}
+}
+
+func TestUCTShouldStopOnVisitShare(t *testing.T) {
+	tests := []struct {
+		name       string
+		visits     []int
+		iterations int
+		managed    bool
+		want       bool
+	}{
+		{"dominant child", []int{95, 5}, 1 << 30, true, true},
+		{"close children", []int{60, 40}, 1 << 30, true, false},
+		{"lead cannot be overturned", []int{70, 30}, 120, true, true},
+		{"fixed time limit", []int{95, 5}, 1 << 30, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEvaluator(UCT, NewEvaluatorOptions(WithBoard(newPickBoard(1, 1, 2))))
+			// 已用时 1 秒，超过软限制的一半但未达到软限制
+			e.tm = &timeManager{start: time.Now().Add(-time.Second), soft: 1500 * time.Millisecond, hard: 10 * time.Second, managed: tt.managed}
+			root := &Node{}
+			for _, v := range tt.visits {
+				root.Children = append(root.Children, &Node{Parent: root, Visits: v})
+				root.Visits += v
+			}
+			var lastBest *Node
+			if got := e.uctShouldStop(root, &lastBest, root.Visits, tt.iterations); got != tt.want {
+				t.Fatalf("stop %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
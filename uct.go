@@ -2,67 +2,200 @@ package gotack
 
 import (
 	"math"
-	"math/rand"
 	"sort"
+	"sync"
 	"time"
 )
 
 type Node struct {
-	State           Board
-	Parent          *Node
-	Children        []*Node
-	Visits          int
-	TotalReward     float64
-	IsMaxPlayer     bool
-	Move            Move
-	UntriedMoves    []Move
-	SimulationCount int // 模拟次数计数器
-	ExpandedCount   int // 已扩展的节点数
+	State         Board
+	Parent        *Node
+	Children      []*Node
+	Visits        int
+	TotalReward   float64
+	IsMaxPlayer   bool
+	Move          Move
+	UntriedMoves  []Move    // 按启发式评估排序后的全部走法
+	UntriedScores []float64 // 与 UntriedMoves 一一对应的启发式评估值
+	ExpandedCount int       // 已扩展的节点数
+	Heuristic     float64   // 到达该节点的走法的启发式评估值，用于渐进偏置
+	IsChance      bool      // 是否为机会节点，机会节点的子节点按概率采样而非按UCT值选择
+	Probability   float64   // 机会节点的子节点对应随机结果的概率
+	Player        int       // 多人棋盘中该节点的行棋方编号
+	TotalRewards  []float64 // 多人棋盘中每个玩家的累计奖励，下标为玩家编号
+	Availability  int       // ISMCTS 中该节点的走法在多少次迭代中是合法的
+
+	movesReady bool   // UntriedMoves 是否已生成
+	hashReady  bool   // stateHash 是否已计算
+	stateHash  uint64 // 缓存的局面哈希，用于重复局面检测
 }
 
 // UCT uses Monte Carlo Tree Search algorithm to evaluate the current board state and return the best move.
+// 当 ThreadNum 大于 1 时使用根节点并行：每个 goroutine 在独立的棋盘克隆和随机流上构建自己的搜索树，最后合并根节点的统计。
+// RootSelection 为 RootGumbel 时改用 Gumbel 根节点选择，此时总是单线程搜索。
 func (e *Evaluator) uct(opts *EvalOptions) (float64, []Move) {
-	root := &Node{State: e.Board, IsMaxPlayer: opts.IsMaxPlayer}
+	if opts.RootSelection == RootGumbel {
+		root := e.newRoot(e.Board)
+		e.tree = root
+		return e.nodeResult(root, e.gumbelSearch(root, opts))
+	}
+	if opts.ThreadNum > 1 {
+		return e.uctParallel(opts)
+	}
+	root := e.newRoot(e.Board)
+	e.tree = root
+	e.runUCT(root, opts, opts.Iterations)
+	return e.selectBestMove(root)
+}
 
-	startTime := time.Now()
-	timeLimit := time.Duration(opts.TimeLimit) * time.Second
-	iterations := opts.Iterations
-	if timeLimit == 0 {
-		timeLimit = time.Duration(math.MaxInt64)
+// newRoot 创建搜索树的根节点。对多人棋盘，轮到 rootPlayer 走棋时视为最大化玩家。
+func (e *Evaluator) newRoot(board Board) *Node {
+	root := &Node{State: board, IsMaxPlayer: e.EvalOptions.IsMaxPlayer}
+	e.stats.TreeSize++
+	if mp, ok := board.(MultiPlayerBoard); ok && e.multiPlayer {
+		root.Player = mp.CurrentPlayer()
+		root.IsMaxPlayer = e.isRootPlayerToMove(mp)
 	}
-	if iterations == 0 {
-		iterations = math.MaxInt32
+	return root
+}
+
+// ply 返回节点距离根节点的步数。
+func (n *Node) ply() int {
+	ply := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		ply++
 	}
+	return ply
+}
 
-	if timeLimit == 0 && iterations == 0 {
-		timeLimit = 10 * time.Second
+// runUCT 在 root 上执行最多 iterations 次蒙特卡洛树搜索迭代，iterations 为 0 表示只受时间限制。
+// 每次迭代都检查硬时间限制，每隔 timeCheckInterval 次迭代通过 uctShouldStop 检查软限制与提前停止。
+func (e *Evaluator) runUCT(root *Node, opts *EvalOptions, iterations int) {
+	if iterations == 0 {
+		iterations = math.MaxInt32
 	}
 
 	// Configuration for expansion and simulation
 	simulationThreshold := getOptionInt(opts.Extra, "SimThresh", 1)
-	expandThreshold := getOptionInt(opts.Extra, "ExpandThresh", 1000)
-	expandStep := getOptionInt(opts.Extra, "ExpandStep", 5)
-	expandTopN := getOptionInt(opts.Extra, "ExpandTopN", 250)
-	aheadStep := getOptionInt(opts.Extra, "AheadStep", 0)
+	aheadStep := opts.rolloutDepth()
 
-	for i := 0; i < iterations; i++ {
-		if time.Since(startTime) >= timeLimit {
+	var lastBest *Node
+	var lastInfo time.Duration
+	for i := 0; i < iterations && e.err == nil; i++ {
+		if i > 0 && (e.tm.hardExpired() || e.nodesExhausted()) {
 			break
 		}
+		if i > 0 && i%timeCheckInterval == 0 {
+			if e.uctShouldStop(root, &lastBest, i, iterations) {
+				break
+			}
+			e.reportTree(root, &lastInfo)
+		}
+		e.nodes++
+		e.stats.Playouts++
 
-		node := e.selectNode(root)
-		if !node.State.IsGameOver() {
-			result := e.simulate(node, aheadStep)
-			e.backpropagate(node, result)
-			node.SimulationCount++
-			if node.SimulationCount >= simulationThreshold {
-				e.expandNode(node, expandThreshold, expandStep, expandTopN)
-				node.SimulationCount = 0
+		node := e.selectNode(root, simulationThreshold)
+		e.reachDepth(node.ply())
+		e.playout(node, aheadStep)
+	}
+}
+
+// playout 从 selectNode 选出的叶节点模拟并反向传播结果。构成重复的叶节点直接按和棋计分，残局库中的叶节点使用精确结果，
+// 已展开却没有合法走法的叶节点按 NoMoves 规则计分，胜负结果都通过 rewardValue 换算为固定奖励。
+// 多人棋盘反向传播整个分数向量，其余棋盘反向传播评估值。
+func (e *Evaluator) playout(node *Node, aheadStep int) {
+	if e.nodeRepeated(node) {
+		e.backpropagate(node, e.drawScore())
+		return
+	}
+	if value, ok := e.probeTablebase(node.State, node.IsMaxPlayer, node.ply()); ok {
+		e.backpropagate(node, e.rewardValue(value))
+		return
+	}
+	// NoMovesEvaluate 直接模拟：没有合法走法时模拟立即结束并评估该局面。
+	if !e.multiPlayer && e.EvalOptions.NoMoves != NoMovesEvaluate && node.movesReady && !node.IsChance &&
+		len(node.UntriedMoves) == 0 && !node.State.IsGameOver() {
+		e.backpropagate(node, e.rewardValue(e.noMovesValue(node.IsMaxPlayer, node.ply())))
+		return
+	}
+	if e.multiPlayer {
+		e.backpropagateScores(node, e.simulateScores(node, aheadStep))
+		return
+	}
+	e.backpropagate(node, e.simulate(node, aheadStep))
+}
+
+// uctParallel 以根节点并行的方式执行 UCT。迭代次数在各 goroutine 之间平均分配，
+// 每个 goroutine 使用从主随机流派生的独立随机流，合并时按 Move.String() 累加根节点子节点的访问次数与奖励。
+func (e *Evaluator) uctParallel(opts *EvalOptions) (float64, []Move) {
+	threads := opts.ThreadNum
+	rngs := e.deriveRands(threads)
+	roots := make([]*Node, threads)
+	workers := make([]*Evaluator, threads)
+
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		iterations := 0
+		if opts.Iterations > 0 {
+			iterations = opts.Iterations / threads
+			if i < opts.Iterations%threads {
+				iterations++
 			}
+			if iterations == 0 {
+				continue
+			}
+		}
+		worker := e.fork(rngs[i])
+		if e.nodeLimit > 0 {
+			worker.nodeLimit = max(e.nodeLimit/int64(threads), 1)
+		}
+		workers[i] = worker
+		roots[i] = worker.newRoot(worker.Board)
+		wg.Add(1)
+		go func(root *Node, iterations int) {
+			defer wg.Done()
+			worker.runUCT(root, opts, iterations)
+		}(roots[i], iterations)
+	}
+	wg.Wait()
+	for _, worker := range workers {
+		if worker != nil && worker.err != nil {
+			e.fail(worker.err)
 		}
 	}
 
-	return e.selectBestMove(root)
+	merged := e.newRoot(e.Board)
+	e.tree = merged
+	index := make(map[string]*Node)
+	for i, root := range roots {
+		if root == nil {
+			continue
+		}
+		e.nodes += workers[i].nodes
+		e.stats.merge(workers[i].stats)
+		merged.Visits += root.Visits
+		merged.TotalReward += root.TotalReward
+		for _, child := range root.Children {
+			key := child.Move.String()
+			m, ok := index[key]
+			if !ok {
+				m = &Node{Parent: merged, IsMaxPlayer: child.IsMaxPlayer, Move: child.Move}
+				index[key] = m
+				merged.Children = append(merged.Children, m)
+			}
+			m.Visits += child.Visits
+			m.TotalReward += child.TotalReward
+			if child.TotalRewards != nil {
+				if m.TotalRewards == nil {
+					m.TotalRewards = make([]float64, len(child.TotalRewards))
+				}
+				for player, reward := range child.TotalRewards {
+					m.TotalRewards[player] += reward
+				}
+			}
+		}
+	}
+	return e.selectBestMove(merged)
 }
 
 // getOptionInt 从配置映射中提取整数值，如果未找到或类型不匹配，则返回默认值。
@@ -89,114 +222,259 @@ func (n *Node) UCTValue(totalVisits int) float64 {
 	return avgReward + exploration
 }
 
+// childUCTValue 返回父节点选择子节点时使用的UCT值。
+// 多人棋盘使用子节点中父节点行棋方的平均奖励，其余棋盘使用 UCTValue。
+func (e *Evaluator) childUCTValue(parent, child *Node) float64 {
+	if !e.multiPlayer || child.Visits == 0 {
+		return child.UCTValue(parent.Visits)
+	}
+	avgReward := child.TotalRewards[parent.Player] / float64(child.Visits)
+	exploration := math.Sqrt(2 * math.Log(float64(parent.Visits)) / float64(child.Visits))
+	return avgReward + exploration
+}
+
 // selectNode 根据UCT值递归选择最优子节点，直到达到叶节点。
-// node 是当前考察的节点。
+// 到达终局或构成重复的节点时停止下降。下降过程中会按渐进展开的规则为沿途节点扩展新的子节点，新扩展的子节点直接作为本次迭代的叶节点。
+// 沿途经过的节点记录在 treePath 中，供本次迭代的重复检测使用。
+// node 是当前考察的节点，simulationThreshold 是节点允许扩展前至少需要的访问次数。
 // 返回选中的叶节点。
-func (e *Evaluator) selectNode(node *Node) *Node {
-	for len(node.Children) > 0 {
+func (e *Evaluator) selectNode(node *Node, simulationThreshold int) *Node {
+	e.resetTreePath(node)
+	for !node.State.IsGameOver() && !e.nodeRepeated(node) {
+		if e.expandChance(node) {
+			e.enterTreeNode(node)
+			node = e.sampleChanceChild(node)
+			continue
+		}
+		if child := e.expandNode(node, simulationThreshold); child != nil {
+			e.enterTreeNode(node)
+			return child
+		}
+		if len(node.Children) == 0 {
+			break
+		}
 		bestUCT := -math.MaxFloat64
 		var bestChild *Node
 		for _, child := range node.Children {
-			uctValue := child.UCTValue(node.Visits)
+			uctValue := e.childUCTValue(node, child) + e.progressiveBias(child)
 			if uctValue > bestUCT {
 				bestUCT = uctValue
 				bestChild = child
 			}
 		}
+		e.enterTreeNode(node)
 		node = bestChild
 	}
 	return node
 }
 
-// expandNode 根据访问次数和扩展阈值动态地在树中扩展新的节点。
-// node 是当前需要扩展的节点，expandThreshold 是节点访问次数的阈值，
-// expandStep 是达到扩展阈值时应该扩展的节点数量，expandTopN 是节点可以扩展的最大子节点数。
-func (e *Evaluator) expandNode(node *Node, expandThreshold int, expandStep int, expandTopN int) {
-	// 首次初始化未尝试的移动列表
-	if len(node.UntriedMoves) == 0 {
-		allMoves := node.State.GetAllMoves(node.IsMaxPlayer)
-		evaluateAndSortMoves(allMoves, node, e.EvalOptions)
-		node.UntriedMoves = allMoves // 存储所有可尝试的移动
-	}
-
-	// 计算应该扩展的次数，即节点访问次数除以扩展阈值
-	numExpansionsNeeded := node.Visits / expandThreshold
-	// 计算当前已经扩展的次数
-	currentExpansionsDone := node.ExpandedCount / expandStep
-
-	if numExpansionsNeeded > currentExpansionsDone {
-		// 计算新的扩展目标，即上次扩展后额外增加的扩展数量
-		additionalExpansions := (numExpansionsNeeded - currentExpansionsDone) * expandStep
-		// 更新目标扩展计数
-		targetExpandCount := min(node.ExpandedCount+additionalExpansions, expandTopN)
-		// 执行扩展操作，直到达到目标扩展计数或未尝试移动用尽
-		for node.ExpandedCount < targetExpandCount && node.ExpandedCount < len(node.UntriedMoves) {
-			move := node.UntriedMoves[node.ExpandedCount]
-			newState := node.State.Clone()
-			newState.Move(move)
-			childNode := &Node{
-				State:       newState,
-				Parent:      node,
-				IsMaxPlayer: !node.IsMaxPlayer,
-				Move:        move,
-			}
-			node.Children = append(node.Children, childNode)
-			node.ExpandedCount++
-		}
+// expandChance 检查节点是否为机会节点，若是则在首次访问时一次性扩展全部随机结果并返回 true。
+// 随机结果不改变行棋方。
+func (e *Evaluator) expandChance(node *Node) bool {
+	if node.movesReady {
+		return node.IsChance
 	}
+	outcomes, ok := chanceOutcomes(node.State)
+	if !ok {
+		return false
+	}
+	for _, outcome := range outcomes {
+		newState := node.State.Clone()
+		newState.Move(outcome.Move)
+		node.Children = append(node.Children, &Node{
+			State:       newState,
+			Parent:      node,
+			IsMaxPlayer: node.IsMaxPlayer,
+			Move:        outcome.Move,
+			Probability: outcome.Probability,
+			Player:      node.Player,
+		})
+	}
+	e.stats.TreeSize += int64(len(outcomes))
+	node.IsChance = true
+	node.movesReady = true
+	return true
 }
-func evaluateAndSortMoves(moves []Move, node *Node, opts *EvalOptions) {
-	moveEvaluations := make([]struct {
-		move  Move
-		value float64
-	}, len(moves))
 
-	for i, move := range moves {
-		newState := node.State.Clone()
-		newState.Move(move)
-		moveEvaluations[i] = struct {
-			move  Move
-			value float64
-		}{
-			move:  move,
-			value: node.State.EvaluateFunc(*opts),
+// sampleChanceChild 按随机结果的概率从机会节点中采样一个子节点。
+func (e *Evaluator) sampleChanceChild(node *Node) *Node {
+	r := e.rng.Float64()
+	for _, child := range node.Children {
+		r -= child.Probability
+		if r < 0 {
+			return child
 		}
 	}
+	return node.Children[len(node.Children)-1]
+}
+
+// allowedChildren 返回访问次数为 visits 的节点按渐进展开允许拥有的子节点数 ⌊K·N^α⌋。
+func (e *Evaluator) allowedChildren(visits int) int {
+	opts := e.EvalOptions
+	allowed := int(opts.WideningK * math.Pow(float64(visits), opts.WideningAlpha))
+	if opts.MaxChildren > 0 && allowed > opts.MaxChildren {
+		allowed = opts.MaxChildren
+	}
+	return allowed
+}
+
+// progressiveBias 返回子节点的渐进偏置项 W·H/(n+1)，其中 H 是走法的启发式评估值，n 是子节点的访问次数。
+// 偏置随访问次数增加而衰减，搜索初期依赖启发式，后期依赖模拟结果。
+func (e *Evaluator) progressiveBias(child *Node) float64 {
+	if e.EvalOptions.ProgressiveBias == 0 {
+		return 0
+	}
+	return e.EvalOptions.ProgressiveBias * child.Heuristic / float64(child.Visits+1)
+}
+
+// expandNode 按渐进展开为节点扩展一个新的子节点。
+// 节点访问次数达到 simulationThreshold 后，若已有子节点数少于 allowedChildren 且仍有未尝试的走法，
+// 则按启发式排序扩展下一个走法并返回新节点，否则返回 nil。
+func (e *Evaluator) expandNode(node *Node, simulationThreshold int) *Node {
+	if node.Visits < simulationThreshold {
+		return nil
+	}
+	e.initMoves(node)
+	if node.ExpandedCount >= len(node.UntriedMoves) || len(node.Children) >= e.allowedChildren(node.Visits) {
+		return nil
+	}
+	return e.addChild(node)
+}
+
+// initMoves 在首次扩展时生成节点的全部走法并按启发式评估值排序。
+func (e *Evaluator) initMoves(node *Node) {
+	if node.movesReady {
+		return
+	}
+	allMoves := e.legalMoves(node.State, node.IsMaxPlayer)
+	node.UntriedScores = evaluateAndSortMoves(allMoves, node, e.EvalOptions)
+	node.UntriedMoves = allMoves // 存储所有可尝试的移动
+	node.movesReady = true
+}
 
-	sort.Slice(moveEvaluations, func(i, j int) bool {
+// addChild 按排序扩展下一个未尝试的走法并返回新的子节点，调用前需确保仍有未尝试的走法。
+func (e *Evaluator) addChild(node *Node) *Node {
+	move := node.UntriedMoves[node.ExpandedCount]
+	newState := node.State.Clone()
+	newState.Move(move)
+	childNode := &Node{
+		State:       newState,
+		Parent:      node,
+		IsMaxPlayer: !node.IsMaxPlayer,
+		Move:        move,
+		Heuristic:   node.UntriedScores[node.ExpandedCount],
+	}
+	if mp, ok := newState.(MultiPlayerBoard); ok && e.multiPlayer {
+		childNode.Player = mp.CurrentPlayer()
+		childNode.IsMaxPlayer = e.isRootPlayerToMove(mp)
+	}
+	node.Children = append(node.Children, childNode)
+	node.ExpandedCount++
+	e.stats.TreeSize++
+	return childNode
+}
+
+// evaluateAndSortMoves 按走后局面的启发式评估值对走法原地排序（对当前行棋方有利的在前），并返回排序后对应的评估值。
+// 评估值相同的走法保持 GetAllMoves 返回的相对顺序。
+func evaluateAndSortMoves(moves []Move, node *Node, opts *EvalOptions) []float64 {
+	values := evaluateMoves(node.State, moves, opts)
+
+	order := make([]int, len(moves))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
 		if node.IsMaxPlayer {
-			return moveEvaluations[i].value > moveEvaluations[j].value
+			return values[order[i]] > values[order[j]]
 		}
-		return moveEvaluations[i].value < moveEvaluations[j].value
+		return values[order[i]] < values[order[j]]
 	})
 
-	for i, eval := range moveEvaluations {
-		moves[i] = eval.move
+	sortedMoves := make([]Move, len(moves))
+	scores := make([]float64, len(moves))
+	for i, idx := range order {
+		sortedMoves[i] = moves[idx]
+		scores[i] = values[idx]
 	}
+	copy(moves, sortedMoves)
+	return scores
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// evaluateMoves 返回 state 上每个走法走后局面的 EvaluateFunc 评估值。
+// 评估在棋盘副本上通过 Move/UndoMove 完成，不会修改 state。
+// MoveEvalThreads 大于 1 时将走法分段并行评估，每个 goroutine 只克隆一次棋盘。
+func evaluateMoves(state Board, moves []Move, opts *EvalOptions) []float64 {
+	values := make([]float64, len(moves))
+	workers := min(opts.MoveEvalThreads, len(moves))
+	if workers <= 1 {
+		evaluateMoveRange(state.Clone(), moves, values, opts)
+		return values
 	}
-	return b
+
+	chunk := (len(moves) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(moves); start += chunk {
+		end := min(start+chunk, len(moves))
+		wg.Add(1)
+		go func(scratch Board, start, end int) {
+			defer wg.Done()
+			evaluateMoveRange(scratch, moves[start:end], values[start:end], opts)
+		}(state.Clone(), start, end)
+	}
+	wg.Wait()
+	return values
 }
 
+// evaluateMoveRange 在 scratch 上依次试走 moves 中的每一步并将评估值写入 values。
+func evaluateMoveRange(scratch Board, moves []Move, values []float64, opts *EvalOptions) {
+	for i, move := range moves {
+		scratch.Move(move)
+		values[i] = scratch.EvaluateFunc(*opts)
+		scratch.UndoMove(move)
+	}
+}
+
+// simulate 从节点出发按 RolloutPolicy 模拟最多 aheadStep 步，并返回终局面的评估值。
 func (e *Evaluator) simulate(node *Node, aheadStep int) float64 {
+	return e.evaluateGameState(e.rollout(node, aheadStep))
+}
+
+// simulateScores 与 simulate 相同，但返回多人棋盘终局面每个玩家的分数。
+func (e *Evaluator) simulateScores(node *Node, aheadStep int) []float64 {
+	e.stats.LeafEvals++
+	state, _ := e.rollout(node, aheadStep)
+	return state.(MultiPlayerBoard).PlayerScores(*e.EvalOptions)
+}
+
+// rollout 在节点状态的副本上按 RolloutPolicy 模拟最多 aheadStep 步，并返回模拟结束时的棋盘以及此时的行棋方是否为最大化玩家。
+func (e *Evaluator) rollout(node *Node, aheadStep int) (Board, bool) {
+	policy := e.EvalOptions.RolloutPolicy
+	if policy == nil {
+		policy = RandomRollout{}
+	}
 	currentState := node.State.Clone()
 	isMaxPlayer := node.IsMaxPlayer
 
 	for steps := 0; steps < aheadStep && !currentState.IsGameOver(); steps++ {
-		moves := currentState.GetAllMoves(isMaxPlayer)
+		if outcomes, ok := chanceOutcomes(currentState); ok {
+			currentState.Move(sampleOutcome(outcomes, e.rng))
+			continue
+		}
+		if mp, ok := currentState.(MultiPlayerBoard); ok && e.multiPlayer {
+			isMaxPlayer = e.isRootPlayerToMove(mp)
+		}
+		moves := e.legalMoves(currentState, isMaxPlayer)
 		if len(moves) == 0 {
 			break
 		}
-		moveIndex := rand.Intn(len(moves))
-		currentState.Move(moves[moveIndex])
+		move := policy.SelectMove(currentState, moves, isMaxPlayer, e.EvalOptions, e.rng)
+		if move == nil {
+			break
+		}
+		currentState.Move(move)
 		isMaxPlayer = !isMaxPlayer
 	}
-
-	return e.evaluateGameState(currentState)
+	return currentState, isMaxPlayer
 }
 
 func (e *Evaluator) backpropagate(node *Node, result float64) {
@@ -207,6 +485,21 @@ func (e *Evaluator) backpropagate(node *Node, result float64) {
 	}
 }
 
+// backpropagateScores 将多人棋盘的分数向量沿路径反向传播，TotalReward 记录根节点行棋方的分数。
+func (e *Evaluator) backpropagateScores(node *Node, scores []float64) {
+	for node != nil {
+		if node.TotalRewards == nil {
+			node.TotalRewards = make([]float64, len(scores))
+		}
+		for player, score := range scores {
+			node.TotalRewards[player] += score
+		}
+		node.Visits++
+		node.TotalReward += scores[e.rootPlayer]
+		node = node.Parent
+	}
+}
+
 func (e *Evaluator) extractMoves(root, bestMove *Node) []Move {
 	var moves []Move
 	current := bestMove
@@ -217,10 +510,47 @@ func (e *Evaluator) extractMoves(root, bestMove *Node) []Move {
 	return moves
 }
 
-func (e *Evaluator) evaluateGameState(state Board) float64 {
+// evaluateGameState 返回模拟结束时的局面以最大化玩家视角的评估值，isMaxPlayer 表示此时的行棋方。
+// 局面已结束且棋盘通过 ResultBoard 给出了明确结果时，胜负换算为 rewardValue 的固定奖励，和棋为 0；否则调用 EvaluateFunc。
+func (e *Evaluator) evaluateGameState(state Board, isMaxPlayer bool) float64 {
+	e.stats.LeafEvals++
+	if rb, ok := state.(ResultBoard); ok && state.IsGameOver() {
+		if result := rb.Result(); result != Unknown {
+			return e.rewardValue(resultValue(result, isMaxPlayer, 0))
+		}
+	}
 	return state.EvaluateFunc(*e.EvalOptions)
 }
 
+// rewardValue 将胜负评估值换算为 MCTS 的固定奖励：胜为 EvalUpperBound，负为 EvalLowerBound，其余评估值保持不变。
+// MCTS 对模拟结果取平均，胜负奖励必须与 EvaluateFunc 处于同一尺度，因此可能出现胜负结果时 checkRewardBounds 要求设置有限的界限。
+func (e *Evaluator) rewardValue(value float64) float64 {
+	if math.Abs(value) < MateScore-maxMatePly {
+		return value
+	}
+	if value > 0 {
+		return e.EvalOptions.EvalUpperBound
+	}
+	return e.EvalOptions.EvalLowerBound
+}
+
+// checkRewardBounds 检查 UCT 与 ISMCTS 的模拟是否可能得到胜负结果（棋盘实现了 ResultBoard、NoMoves 规则为 NoMovesLoss
+// 或设置了残局库），此时若没有通过 WithEvalBounds 设置有限的评估值界限则返回 ErrEvalBoundsRequired。
+// 多人棋盘按 PlayerScores 模拟，不受影响。
+func (e *Evaluator) checkRewardBounds() error {
+	opts := e.EvalOptions
+	if e.multiPlayer {
+		return nil
+	}
+	if _, ok := e.Board.(ResultBoard); !ok && opts.NoMoves != NoMovesLoss && opts.Tablebase == nil {
+		return nil
+	}
+	if math.IsInf(opts.EvalLowerBound, 0) || math.IsInf(opts.EvalUpperBound, 0) {
+		return ErrEvalBoundsRequired
+	}
+	return nil
+}
+
 func (e *Evaluator) selectBestMove(root *Node) (float64, []Move) {
 	var bestMove *Node
 	maxVisits := -1
@@ -230,8 +560,36 @@ func (e *Evaluator) selectBestMove(root *Node) (float64, []Move) {
 			maxVisits = child.Visits
 		}
 	}
+	return e.nodeResult(root, bestMove)
+}
+
+// nodeResult 返回根节点选中子节点的平均奖励以及到达该节点的走法。
+func (e *Evaluator) nodeResult(root, bestMove *Node) (float64, []Move) {
 	if bestMove != nil {
+		e.pv = principalVariation(bestMove)
 		return bestMove.TotalReward / float64(bestMove.Visits), e.extractMoves(root, bestMove)
 	}
 	return 0.0, nil
 }
+
+// principalVariation 返回从 node 的走法开始、沿访问次数最多的子节点得到的走法序列，遇到机会节点时停止。
+func principalVariation(node *Node) []Move {
+	var pv []Move
+	for node != nil && node.Move != nil {
+		pv = append(pv, node.Move)
+		if node.IsChance {
+			break
+		}
+		var next *Node
+		for _, child := range node.Children {
+			if next == nil || child.Visits > next.Visits {
+				next = child
+			}
+		}
+		if next == nil || next.Visits == 0 {
+			break
+		}
+		node = next
+	}
+	return pv
+}
@@ -0,0 +1,315 @@
+package gotack
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+// pickMove 是 pickBoard 的走法，值为所选分数的下标。
+type pickMove int
+
+func (m pickMove) String() string { return strconv.Itoa(int(m)) }
+
+// pickBoard 是一个确定性的测试棋盘：每一步都从 scores 中选择一个下标，共走 depth 步，
+// 局面的评估值为已选下标对应分数之和，因此走一步后的局面分数就是该走法的分数加上当前分数。
+type pickBoard struct {
+	scores []float64
+	depth  int
+	path   []pickMove
+}
+
+func newPickBoard(depth int, scores ...float64) *pickBoard {
+	return &pickBoard{scores: scores, depth: depth}
+}
+
+func (b *pickBoard) Print() { fmt.Println(b.path) }
+
+func (b *pickBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() {
+		return nil
+	}
+	moves := make([]Move, len(b.scores))
+	for i := range moves {
+		moves[i] = pickMove(i)
+	}
+	return moves
+}
+
+func (b *pickBoard) Move(move Move) { b.path = append(b.path, move.(pickMove)) }
+
+func (b *pickBoard) UndoMove(move Move) { b.path = b.path[:len(b.path)-1] }
+
+func (b *pickBoard) IsGameOver() bool { return len(b.path) >= b.depth }
+
+func (b *pickBoard) EvaluateFunc(opts EvalOptions) float64 {
+	value := 0.0
+	for _, m := range b.path {
+		value += b.scores[m]
+	}
+	return value
+}
+
+func (b *pickBoard) Hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, m := range b.path {
+		h = (h ^ uint64(m+1)) * 1099511628211
+	}
+	return h
+}
+
+func (b *pickBoard) Clone() Board {
+	return &pickBoard{scores: b.scores, depth: b.depth, path: append([]pickMove(nil), b.path...)}
+}
+
+func childIndexes(node *Node) []int {
+	indexes := make([]int, len(node.Children))
+	for i, child := range node.Children {
+		indexes[i] = int(child.Move.(pickMove))
+	}
+	return indexes
+}
+
+func TestProgressiveWideningChildCount(t *testing.T) {
+	tests := []struct {
+		k, alpha    float64
+		maxChildren int
+	}{
+		{1, 0.5, 0},
+		{2, 0.5, 0},
+		{1, 0.25, 0},
+		{1.5, 0.7, 0},
+		{2, 0.5, 4},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("k=%v,alpha=%v,max=%d", tt.k, tt.alpha, tt.maxChildren), func(t *testing.T) {
+			board := newPickBoard(2, 3, 1, 4, 1, 5, 9, 2, 6)
+			e := NewEvaluator(UCT, NewEvaluatorOptions(
+				WithBoard(board),
+				WithProgressiveWidening(tt.k, tt.alpha),
+				WithMaxChildren(tt.maxChildren),
+			))
+			node := &Node{State: board, IsMaxPlayer: true}
+			for visits := 1; visits <= 40; visits++ {
+				node.Visits = visits
+				for e.expandNode(node, 1) != nil {
+				}
+				want := int(math.Floor(tt.k * math.Pow(float64(visits), tt.alpha)))
+				if tt.maxChildren > 0 {
+					want = min(want, tt.maxChildren)
+				}
+				want = min(want, len(board.scores))
+				if got := len(node.Children); got != want {
+					t.Fatalf("after %d visits: %d children, want %d", visits, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandNodeBelowThreshold(t *testing.T) {
+	board := newPickBoard(2, 1, 2, 3)
+	e := NewEvaluator(UCT, NewEvaluatorOptions(WithBoard(board), WithProgressiveWidening(10, 1)))
+	node := &Node{State: board, IsMaxPlayer: true, Visits: 2}
+	if child := e.expandNode(node, 3); child != nil {
+		t.Fatalf("expanded %v before reaching the simulation threshold", child.Move)
+	}
+	node.Visits = 3
+	if child := e.expandNode(node, 3); child == nil {
+		t.Fatal("no child expanded at the simulation threshold")
+	}
+}
+
+func TestProgressiveWideningExpandsBestHeuristicFirst(t *testing.T) {
+	board := newPickBoard(1, 1, 5, 3, 2)
+	e := NewEvaluator(UCT, NewEvaluatorOptions(WithBoard(board), WithProgressiveWidening(1, 0.5)))
+	node := &Node{State: board, IsMaxPlayer: true}
+	for visits := 1; visits <= 16; visits++ {
+		node.Visits = visits
+		for e.expandNode(node, 1) != nil {
+		}
+	}
+	want := []int{1, 2, 3, 0}
+	if got := childIndexes(node); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expansion order %v, want %v", got, want)
+	}
+	for i, child := range node.Children {
+		if child.Heuristic != board.scores[want[i]] {
+			t.Errorf("child %d heuristic %v, want %v", want[i], child.Heuristic, board.scores[want[i]])
+		}
+	}
+}
+
+func TestProgressiveBiasSelectsBestHeuristic(t *testing.T) {
+	board := newPickBoard(1, 1, 5, 3, 2)
+	e := NewEvaluator(UCT, NewEvaluatorOptions(
+		WithBoard(board),
+		WithProgressiveWidening(100, 1),
+		WithProgressiveBias(2),
+	))
+	root := &Node{State: board, IsMaxPlayer: true, Visits: 1}
+	for e.expandNode(root, 1) != nil {
+	}
+	if len(root.Children) != len(board.scores) {
+		t.Fatalf("%d children expanded, want %d", len(root.Children), len(board.scores))
+	}
+	// 走法 2 的模拟结果最好，但走法 1 的启发式评估值最高，偏置足以让后者先被选中。
+	for _, child := range root.Children {
+		child.Visits = 1
+		if child.Move == pickMove(2) {
+			child.TotalReward = 1
+		}
+	}
+	root.Visits = len(root.Children)
+	if got := e.selectNode(root, 1).Move; got != pickMove(1) {
+		t.Fatalf("selected %v, want the best heuristic move 1", got)
+	}
+
+	e.EvalOptions.ProgressiveBias = 0
+	if got := e.selectNode(root, 1).Move; got != pickMove(2) {
+		t.Fatalf("without bias selected %v, want the best simulated move 2", got)
+	}
+}
+
+func TestUCTFirstExpansionIsBestHeuristic(t *testing.T) {
+	board := newPickBoard(2, 1, 5, 3, 2)
+	e := NewEvaluator(UCT, NewEvaluatorOptions(
+		WithBoard(board),
+		WithIterations(2),
+		WithSeed(1),
+		WithProgressiveBias(1),
+	))
+	if _, err := e.Search(); err != nil {
+		t.Fatal(err)
+	}
+	if got := childIndexes(e.tree); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("root children after two iterations %v, want [1]", got)
+	}
+}
+
+func TestEvaluateAndSortMovesByChildScore(t *testing.T) {
+	// 根局面已走过下标 0，走后局面的分数为 2 加上走法的分数；走法 1 与 4 分数相同，保持原有顺序。
+	board := newPickBoard(3, 2, -1, 5, 0, -1, 3)
+	board.Move(pickMove(0))
+	tests := []struct {
+		isMaxPlayer bool
+		wantOrder   []int
+		wantScores  []float64
+	}{
+		{true, []int{2, 5, 0, 3, 1, 4}, []float64{7, 5, 4, 2, 1, 1}},
+		{false, []int{1, 4, 3, 0, 5, 2}, []float64{1, 1, 2, 4, 5, 7}},
+	}
+	for _, tt := range tests {
+		for _, threads := range []int{1, 2, 4, 16} {
+			t.Run(fmt.Sprintf("max=%v,threads=%d", tt.isMaxPlayer, threads), func(t *testing.T) {
+				opts := NewEvaluatorOptions(WithBoard(board), WithMoveEvalThreads(threads))
+				moves := board.GetAllMoves(tt.isMaxPlayer)
+				scores := evaluateAndSortMoves(moves, &Node{State: board, IsMaxPlayer: tt.isMaxPlayer}, opts)
+				if got := moveIndexes(moves); fmt.Sprint(got) != fmt.Sprint(tt.wantOrder) {
+					t.Errorf("order %v, want %v", got, tt.wantOrder)
+				}
+				if fmt.Sprint(scores) != fmt.Sprint(tt.wantScores) {
+					t.Errorf("scores %v, want %v", scores, tt.wantScores)
+				}
+				if len(board.path) != 1 {
+					t.Errorf("evaluation changed the board: path %v", board.path)
+				}
+			})
+		}
+	}
+}
+
+// moveIndexes 返回走法对应的下标，便于比较顺序。
+func moveIndexes(moves []Move) []int {
+	indexes := make([]int, len(moves))
+	for i, m := range moves {
+		indexes[i] = int(m.(pickMove))
+	}
+	return indexes
+}
+
+// resultPickBoard 在 pickBoard 上实现 ResultBoard：走法 0 后最大化玩家获胜，其余走法为和棋，
+// 而 EvaluateFunc 的分数与结果相反，用于确认搜索使用的是明确的结果。
+type resultPickBoard struct {
+	*pickBoard
+}
+
+func (b resultPickBoard) Result() GameResult {
+	if b.path[0] == 0 {
+		return Loss // 终局时轮到最小化玩家走棋
+	}
+	return Draw
+}
+
+func (b resultPickBoard) Clone() Board {
+	return resultPickBoard{b.pickBoard.Clone().(*pickBoard)}
+}
+
+func TestUCTUsesExplicitResult(t *testing.T) {
+	opts := []EvalOption{
+		WithBoard(resultPickBoard{newPickBoard(1, -5, 5, 5)}),
+		WithIterations(300),
+		WithTimeLimit(0),
+		WithSeed(1),
+	}
+	if _, err := NewEvaluator(UCT, NewEvaluatorOptions(opts...)).Search(); !errors.Is(err, ErrEvalBoundsRequired) {
+		t.Fatalf("without bounds: error %v, want ErrEvalBoundsRequired", err)
+	}
+
+	result, err := NewEvaluator(UCT, NewEvaluatorOptions(append(opts, WithEvalBounds(-10, 10))...)).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(0) {
+		t.Fatalf("best moves %v, want [0]", result.BestMoves)
+	}
+	if result.Value != 10 || result.MateIn != 0 {
+		t.Errorf("value %v mate in %d, want 10 and 0", result.Value, result.MateIn)
+	}
+}
+
+// quickWinBoard 在 pickBoard 上实现 ResultBoard：走法 0 立即结束对局并由最大化玩家获胜，其余走法继续对局。
+type quickWinBoard struct {
+	*pickBoard
+}
+
+func (b quickWinBoard) IsGameOver() bool {
+	return b.pickBoard.IsGameOver() || len(b.path) > 0 && b.path[0] == 0
+}
+
+func (b quickWinBoard) GetAllMoves(isMaxPlayer bool) []Move {
+	if b.IsGameOver() {
+		return nil
+	}
+	return b.pickBoard.GetAllMoves(isMaxPlayer)
+}
+
+func (b quickWinBoard) Result() GameResult {
+	if b.path[0] == 0 {
+		return Loss // 终局时轮到最小化玩家走棋
+	}
+	return Draw
+}
+
+func (b quickWinBoard) Clone() Board {
+	return quickWinBoard{b.pickBoard.Clone().(*pickBoard)}
+}
+
+func TestUCTPrefersWinOverHeuristic(t *testing.T) {
+	// 走法 0 直接获胜，其余走法之后的局面评估值为 50，远大于 ±1，但仍在评估值界限之内。
+	result, err := NewEvaluator(UCT, NewEvaluatorOptions(
+		WithBoard(quickWinBoard{newPickBoard(3, -5, 50, 50)}),
+		WithIterations(300),
+		WithTimeLimit(0),
+		WithSeed(1),
+		WithEvalBounds(-100, 100),
+	)).Search()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.BestMoves) != 1 || result.BestMoves[0] != pickMove(0) {
+		t.Fatalf("best moves %v, want the winning move [0]", result.BestMoves)
+	}
+}